@@ -0,0 +1,178 @@
+package mint
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+//Authentication headers and schemes
+const (
+	headerAuthorization   = "Authorization"
+	headerWWWAuthenticate = "WWW-Authenticate"
+	headerAPIKey          = "X-API-Key"
+	SchemeBasic           = "Basic"
+	SchemeBearer          = "Bearer"
+	SchemeAPIKey          = "APIKey"
+)
+
+//Principal is authenticated identity of the request
+type Principal struct {
+	//Name is user name, it is logged by logger middleware
+	Name string
+	//Scheme is authentication scheme used
+	Scheme string
+	//Data is any value verifier wants to keep with principal
+	Data interface{}
+}
+
+//BasicAuthVerifier verifies username and password
+type BasicAuthVerifier func(c *Context, username string, password string) bool
+
+//TokenVerifier verifies token and returns principal for it
+type TokenVerifier func(c *Context, token string) (*Principal, bool)
+
+//APIKeyConfig configures API key middleware
+type APIKeyConfig struct {
+	//Header is request header containing API key, default is X-API-Key
+	Header string
+	//Query is name of query parameter containing API key
+	//It is used when header is not present
+	Query string
+	//Verifier verifies the key
+	Verifier TokenVerifier
+}
+
+//BasicAuthAccounts creates BasicAuthVerifier from username and password pairs
+func BasicAuthAccounts(accounts map[string]string) BasicAuthVerifier {
+	return func(c *Context, username string, password string) bool {
+		expected, ok := accounts[username]
+		if !ok {
+			return false
+		}
+		return subtle.ConstantTimeCompare([]byte(expected), []byte(password)) == 1
+	}
+}
+
+//StaticTokens creates TokenVerifier from token and user name pairs
+func StaticTokens(tokens map[string]string) TokenVerifier {
+	return func(c *Context, token string) (*Principal, bool) {
+		for key, name := range tokens {
+			if subtle.ConstantTimeCompare([]byte(key), []byte(token)) == 1 {
+				return &Principal{Name: name}, true
+			}
+		}
+		return nil, false
+	}
+}
+
+//BasicAuth creates basic authentication middleware
+func BasicAuth(realm string, verifier BasicAuthVerifier) HandlerFunc {
+	if realm == emptyString {
+		realm = "Authorization Required"
+	}
+	challenge := SchemeBasic + " realm=" + strconv.Quote(realm)
+	return func(c *Context) {
+		username, password, ok := c.Req.BasicAuth()
+		if !ok || !verifier(c, username, password) {
+			unauthorized(c, challenge)
+			return
+		}
+		c.SetPrincipal(&Principal{Name: username, Scheme: SchemeBasic})
+		c.Next()
+	}
+}
+
+//BearerAuth creates bearer token authentication middleware
+func BearerAuth(verifier TokenVerifier) HandlerFunc {
+	return func(c *Context) {
+		token, ok := bearerToken(c)
+		if !ok {
+			unauthorized(c, SchemeBearer)
+			return
+		}
+		principal, ok := verifier(c, token)
+		if !ok {
+			unauthorized(c, SchemeBearer+` error="invalid_token"`)
+			return
+		}
+		setPrincipal(c, principal, SchemeBearer)
+		c.Next()
+	}
+}
+
+//APIKeyAuth creates API key authentication middleware
+func APIKeyAuth(config APIKeyConfig) HandlerFunc {
+	header := config.Header
+	if header == emptyString && config.Query == emptyString {
+		header = headerAPIKey
+	}
+	return func(c *Context) {
+		var key string
+		if header != emptyString {
+			key = c.GetHeader(header)
+		}
+		if key == emptyString && config.Query != emptyString {
+			key, _ = c.Query(config.Query)
+		}
+		if key == emptyString {
+			ErrorMessage(c, http.StatusUnauthorized, "API key required")
+			return
+		}
+		principal, ok := config.Verifier(c, key)
+		if !ok {
+			ErrorMessage(c, http.StatusUnauthorized, "Invalid API key")
+			return
+		}
+		setPrincipal(c, principal, SchemeAPIKey)
+		c.Next()
+	}
+}
+
+func bearerToken(c *Context) (string, bool) {
+	authorization := c.GetHeader(headerAuthorization)
+	if len(authorization) <= len(SchemeBearer)+1 ||
+		!strings.EqualFold(authorization[:len(SchemeBearer)], SchemeBearer) ||
+		authorization[len(SchemeBearer)] != ' ' {
+		return emptyString, false
+	}
+	token := strings.TrimSpace(authorization[len(SchemeBearer)+1:])
+	return token, token != emptyString
+}
+
+func setPrincipal(c *Context, principal *Principal, scheme string) {
+	if principal == nil {
+		principal = new(Principal)
+	}
+	if principal.Scheme == emptyString {
+		//verifier may return shared principal, so it is not modified
+		copied := *principal
+		copied.Scheme = scheme
+		principal = &copied
+	}
+	c.SetPrincipal(principal)
+}
+
+func unauthorized(c *Context, challenge string) {
+	c.Res.Header().Set(headerWWWAuthenticate, challenge)
+	ErrorMessage(c, http.StatusUnauthorized, "Unauthorized")
+}
+
+//SetPrincipal sets authenticated principal of the request
+func (c *Context) SetPrincipal(principal *Principal) {
+	c.principal = principal
+}
+
+//Principal returns authenticated principal of the request, nil if not authenticated
+func (c *Context) Principal() *Principal {
+	return c.principal
+}
+
+//UserName returns name of authenticated user
+func (c *Context) UserName() string {
+	if c.principal == nil {
+		return emptyString
+	}
+	return c.principal.Name
+}
@@ -3,20 +3,31 @@ package mint
 import (
 	"bytes"
 	"sync"
+	"sync/atomic"
 )
 
 //BufferPool #
 type BufferPool struct {
-	p *sync.Pool
+	//allocated is first field for 64 bit alignment of atomic operations
+	allocated uint64
+	p         *sync.Pool
 }
 
 //NewBufferPool #
 func NewBufferPool() *BufferPool {
-	return &BufferPool{
-		p: &sync.Pool{
-			New: func() interface{} { return new(bytes.Buffer) },
+	bpool := new(BufferPool)
+	bpool.p = &sync.Pool{
+		New: func() interface{} {
+			atomic.AddUint64(&bpool.allocated, 1)
+			return new(bytes.Buffer)
 		},
 	}
+	return bpool
+}
+
+//Allocated returns number of buffers allocated by the pool
+func (bpool *BufferPool) Allocated() uint64 {
+	return atomic.LoadUint64(&bpool.allocated)
 }
 
 //Get #
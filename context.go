@@ -8,6 +8,8 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync/atomic"
+	"time"
 )
 
 //constant
@@ -36,9 +38,20 @@ type Context struct {
 	size           int
 	errors         []error
 	query          url.Values
+	principal      *Principal
+	claims         Claims
+	rawClaims      []byte
+	csrfToken      string
+	cspNonce       string
+	apiVersion     string
+	paramValues    map[string]interface{}
+	keys           map[interface{}]interface{}
+	scope          *serviceScope
+	span           *Span
 }
 
 func (app *Mint) newContext() *Context {
+	atomic.AddUint64(&app.pools.Contexts, 1)
 	return new(Context)
 }
 
@@ -53,6 +66,18 @@ func (c *Context) Reset() {
 	c.index = 0
 	c.params = nil
 	c.query = nil
+	c.principal = nil
+	c.claims = nil
+	c.rawClaims = nil
+	c.csrfToken = emptyString
+	c.cspNonce = emptyString
+	c.apiVersion = emptyString
+	c.paramValues = nil
+	c.scope = nil
+	c.span = nil
+	for key := range c.keys {
+		delete(c.keys, key)
+	}
 }
 func newContextPool(app *Mint) func() interface{} {
 	return func() interface{} {
@@ -72,6 +97,19 @@ func (c *Context) Status(status int) {
 	c.Res.WriteHeader(status)
 }
 
+//responseStatus returns status of response, finished is false when chain
+//did not return such as when handler panics. Response without status is
+//200 if chain finished, otherwise it is not complete and counted as 500
+func (c *Context) responseStatus(finished bool) int {
+	if c.status != 0 {
+		return c.status
+	}
+	if finished {
+		return http.StatusOK
+	}
+	return http.StatusInternalServerError
+}
+
 func (c *Context) writeContentType(values []string) {
 	c.SetHeader(contentType, values)
 }
@@ -180,6 +218,11 @@ func (c *Context) Errors(err ...error) {
 	}
 }
 
+//RecordedErrors returns errors recorded in context
+func (c *Context) RecordedErrors() []error {
+	return c.errors
+}
+
 func (c *Context) Error(err error) {
 	if err != nil {
 		c.errors = append(c.errors, err)
@@ -246,49 +289,119 @@ func (c *Context) QueryValues() url.Values {
 	return c.query
 }
 
-//Get #
+//Lookup returns value stored using key, values set by
+//net/http middleware in Req.Context() are looked up as well
+func (c *Context) Lookup(key interface{}) (interface{}, bool) {
+	if value, ok := c.keys[key]; ok {
+		return value, true
+	}
+	if c.Req == nil {
+		return nil, false
+	}
+	value := c.Req.Context().Value(key)
+	return value, value != nil
+}
+
+//Get returns value stored using key, nil if not found
 func (c *Context) Get(key interface{}) interface{} {
-	return c.Req.Context().Value(key)
+	value, _ := c.Lookup(key)
+	return value
 }
 
 //DefaultGet #
 func (c *Context) DefaultGet(key interface{}, defaultv interface{}) interface{} {
-	value := c.Get(key)
-	if value == nil {
+	value, ok := c.Lookup(key)
+	if !ok {
 		return defaultv
 	}
 	return value
 }
 
-//GetString gets string value using key in context
-func (c *Context) GetString(key interface{}) string {
-	return c.Get(key).(string)
+//GetString gets value associated with key as string
+func (c *Context) GetString(key interface{}) (string, bool) {
+	value, ok := c.Get(key).(string)
+	return value, ok
+}
+
+//GetBool gets value associated with key as bool
+func (c *Context) GetBool(key interface{}) (bool, bool) {
+	value, ok := c.Get(key).(bool)
+	return value, ok
 }
 
-//GetInt64 gets values associated with key as int64
-func (c *Context) GetInt64(key interface{}) int64 {
-	return c.Get(key).(int64)
+//GetInt gets value associated with key as int
+func (c *Context) GetInt(key interface{}) (int, bool) {
+	value, ok := c.Get(key).(int)
+	return value, ok
 }
 
-//GetFloat64 gets values associated with key as float64
-func (c *Context) GetFloat64(key interface{}) float64 {
-	return c.Get(key).(float64)
+//GetInt64 gets value associated with key as int64
+func (c *Context) GetInt64(key interface{}) (int64, bool) {
+	value, ok := c.Get(key).(int64)
+	return value, ok
 }
 
-//GetComplex128 gets values associated with key as complex128
-func (c *Context) GetComplex128(key interface{}) complex128 {
-	return c.Get(key).(complex128)
+//GetFloat64 gets value associated with key as float64
+func (c *Context) GetFloat64(key interface{}) (float64, bool) {
+	value, ok := c.Get(key).(float64)
+	return value, ok
 }
 
-//Set #
+//GetComplex128 gets value associated with key as complex128
+func (c *Context) GetComplex128(key interface{}) (complex128, bool) {
+	value, ok := c.Get(key).(complex128)
+	return value, ok
+}
+
+//GetTime gets value associated with key as time.Time
+func (c *Context) GetTime(key interface{}) (time.Time, bool) {
+	value, ok := c.Get(key).(time.Time)
+	return value, ok
+}
+
+//GetDuration gets value associated with key as time.Duration
+func (c *Context) GetDuration(key interface{}) (time.Duration, bool) {
+	value, ok := c.Get(key).(time.Duration)
+	return value, ok
+}
+
+//GetStringSlice gets value associated with key as []string
+func (c *Context) GetStringSlice(key interface{}) ([]string, bool) {
+	value, ok := c.Get(key).([]string)
+	return value, ok
+}
+
+//Set stores value using key for rest of the request,
+//value is not visible in Req.Context(), use SetRequestValue for that
 func (c *Context) Set(key, val interface{}) {
-	if val == nil {
-		return
+	if c.keys == nil {
+		c.keys = make(map[interface{}]interface{})
 	}
+	c.keys[key] = val
+}
+
+//SetRequestValue stores value using key and also adds it to Req.Context(),
+//so net/http handlers and libraries reading request context can see it
+func (c *Context) SetRequestValue(key, val interface{}) {
+	c.Set(key, val)
 	c.Req = c.Req.WithContext(context.WithValue(c.Req.Context(), key, val))
 }
 
-//Param #
+//Delete removes value stored using key
+func (c *Context) Delete(key interface{}) {
+	delete(c.keys, key)
+}
+
+//Keys returns copy of values stored in context
+func (c *Context) Keys() map[interface{}]interface{} {
+	keys := make(map[interface{}]interface{}, len(c.keys))
+	for key, value := range c.keys {
+		keys[key] = value
+	}
+	return keys
+}
+
+//Param returns path or host variable
 func (c *Context) Param(key string) (string, bool) {
 	value, ok := c.params[key]
 	if ok {
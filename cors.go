@@ -0,0 +1,280 @@
+package mint
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+//CORS headers
+const (
+	headerOrigin                        = "Origin"
+	headerVary                          = "Vary"
+	headerAccessControlRequestMethod    = "Access-Control-Request-Method"
+	headerAccessControlRequestHeaders   = "Access-Control-Request-Headers"
+	headerAccessControlAllowOrigin      = "Access-Control-Allow-Origin"
+	headerAccessControlAllowMethods     = "Access-Control-Allow-Methods"
+	headerAccessControlAllowHeaders     = "Access-Control-Allow-Headers"
+	headerAccessControlAllowCredentials = "Access-Control-Allow-Credentials"
+	headerAccessControlExposeHeaders    = "Access-Control-Expose-Headers"
+	headerAccessControlMaxAge           = "Access-Control-Max-Age"
+)
+
+var defaultCORSMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+}
+
+//CORSConfig configures CORS middleware
+type CORSConfig struct {
+	//AllowedOrigins is list of origins allowed to make cross origin requests.
+	//Origin can contain one wildcard "*" such as "https://*.example.com",
+	//"*" alone allows any origin. Default is "*", it cannot be used with AllowCredentials
+	AllowedOrigins []string
+	//AllowOriginFunc validates origin, it is used when AllowedOrigins does not match
+	AllowOriginFunc func(origin string) bool
+	//AllowedMethods is list of methods allowed in cross origin requests
+	//Default is GET, HEAD, POST, PUT, PATCH and DELETE
+	AllowedMethods []string
+	//AllowedHeaders is list of request headers allowed in cross origin requests
+	//If it is empty, headers requested in preflight are allowed
+	AllowedHeaders []string
+	//ExposedHeaders is list of response headers exposed to client
+	ExposedHeaders []string
+	//AllowCredentials allows cookies and authorization headers,
+	//allowed origins must be listed explicitly
+	AllowCredentials bool
+	//MaxAge is number of seconds the preflight result can be cached
+	MaxAge int
+}
+
+type cors struct {
+	anyOrigin        bool
+	origins          []string
+	patterns         [][2]string
+	allowOriginFunc  func(origin string) bool
+	methods          string
+	headers          string
+	anyHeader        bool
+	exposedHeaders   string
+	allowCredentials bool
+	maxAge           string
+	//scoped CORS of application or group handles only requests
+	//which are not handled by CORS of nested group
+	scoped bool
+	group  *HandlersGroup
+}
+
+//CORS creates new CORS middleware
+//Register it before authentication middlewares so that preflight requests are answered.
+//It panics when any origin is allowed with credentials, as browsers forbid it
+func CORS(config CORSConfig) HandlerFunc {
+	return newCORS(config).handle
+}
+
+func newCORS(config CORSConfig) *cors {
+	cr := new(cors)
+	origins := config.AllowedOrigins
+	if len(origins) == 0 && config.AllowOriginFunc == nil {
+		if config.AllowCredentials {
+			panic("mint: CORS with credentials requires AllowedOrigins or AllowOriginFunc")
+		}
+		origins = []string{"*"}
+	}
+	for _, origin := range origins {
+		origin = strings.ToLower(origin)
+		if origin == "*" {
+			if config.AllowCredentials {
+				panic("mint: CORS cannot allow any origin with credentials")
+			}
+			cr.anyOrigin = true
+		} else if index := strings.IndexByte(origin, '*'); index >= 0 {
+			cr.patterns = append(cr.patterns, [2]string{origin[:index], origin[index+1:]})
+		} else {
+			cr.origins = append(cr.origins, origin)
+		}
+	}
+	cr.allowOriginFunc = config.AllowOriginFunc
+	methods := config.AllowedMethods
+	if len(methods) == 0 {
+		methods = defaultCORSMethods
+	}
+	for index, method := range methods {
+		if index > 0 {
+			cr.methods += ", "
+		}
+		cr.methods += strings.ToUpper(method)
+	}
+	for _, header := range config.AllowedHeaders {
+		if header == "*" {
+			cr.anyHeader = true
+		}
+	}
+	if len(config.AllowedHeaders) == 0 {
+		cr.anyHeader = true
+	}
+	if !cr.anyHeader {
+		cr.headers = strings.Join(config.AllowedHeaders, ", ")
+	}
+	cr.exposedHeaders = strings.Join(config.ExposedHeaders, ", ")
+	cr.allowCredentials = config.AllowCredentials
+	if config.MaxAge > 0 {
+		cr.maxAge = strconv.Itoa(config.MaxAge)
+	}
+	return cr
+}
+
+func (cr *cors) isOriginAllowed(origin string) bool {
+	if cr.anyOrigin {
+		return true
+	}
+	lowerOrigin := strings.ToLower(origin)
+	for _, allowed := range cr.origins {
+		if allowed == lowerOrigin {
+			return true
+		}
+	}
+	for _, pattern := range cr.patterns {
+		if len(lowerOrigin) >= len(pattern[0])+len(pattern[1]) &&
+			strings.HasPrefix(lowerOrigin, pattern[0]) &&
+			strings.HasSuffix(lowerOrigin, pattern[1]) {
+			return true
+		}
+	}
+	if cr.allowOriginFunc != nil {
+		return cr.allowOriginFunc(origin)
+	}
+	return false
+}
+
+func (cr *cors) setOrigin(header http.Header, origin string) {
+	if cr.anyOrigin {
+		header.Set(headerAccessControlAllowOrigin, "*")
+	} else {
+		header.Set(headerAccessControlAllowOrigin, origin)
+	}
+	if cr.allowCredentials {
+		header.Set(headerAccessControlAllowCredentials, "true")
+	}
+}
+
+func (cr *cors) handle(c *Context) {
+	if cr.scoped && c.HandlerContext.corsGroup() != cr.group {
+		c.Next()
+		return
+	}
+	origin := c.GetHeader(headerOrigin)
+	header := c.Res.Header()
+	if isPreflight(c.Req) {
+		header.Add(headerVary, headerOrigin)
+		header.Add(headerVary, headerAccessControlRequestMethod)
+		header.Add(headerVary, headerAccessControlRequestHeaders)
+		if !cr.isOriginAllowed(origin) {
+			ErrorMessage(c, http.StatusForbidden, "Origin not allowed")
+			return
+		}
+		cr.setOrigin(header, origin)
+		header.Set(headerAccessControlAllowMethods, cr.methods)
+		if cr.anyHeader {
+			if requested := c.GetHeader(headerAccessControlRequestHeaders); requested != emptyString {
+				header.Set(headerAccessControlAllowHeaders, requested)
+			}
+		} else {
+			header.Set(headerAccessControlAllowHeaders, cr.headers)
+		}
+		if cr.maxAge != emptyString {
+			header.Set(headerAccessControlMaxAge, cr.maxAge)
+		}
+		c.Status(http.StatusNoContent)
+		return
+	}
+	if origin != emptyString {
+		header.Add(headerVary, headerOrigin)
+		if cr.isOriginAllowed(origin) {
+			cr.setOrigin(header, origin)
+			if cr.exposedHeaders != emptyString {
+				header.Set(headerAccessControlExposeHeaders, cr.exposedHeaders)
+			}
+		}
+	}
+	c.Next()
+}
+
+//isPreflight checks whether the request is CORS preflight request
+func isPreflight(req *http.Request) bool {
+	return req.Method == http.MethodOptions &&
+		req.Header.Get(headerOrigin) != emptyString &&
+		req.Header.Get(headerAccessControlRequestMethod) != emptyString
+}
+
+//preflightMatcher matches preflight requests to paths having routes,
+//so preflight of unknown path is not answered
+func (mt *Mint) preflightMatcher(req *http.Request, match *mux.RouteMatch) bool {
+	return isPreflight(req) && mt.hasRoute(req)
+}
+
+//hasRoute checks whether a route matches the request when method is ignored
+func (mt *Mint) hasRoute(req *http.Request) bool {
+	for _, hc := range mt.routes {
+		if hc.route == nil {
+			continue
+		}
+		var match mux.RouteMatch
+		if hc.route.Match(req, &match) || match.MatchErr == mux.ErrMethodMismatch {
+			return true
+		}
+	}
+	return false
+}
+
+//CORS registers CORS middleware for whole application,
+//preflight requests are answered before route method matching rejects them.
+//Groups having their own CORS answer preflight requests to their paths
+func (mt *Mint) CORS(config CORSConfig) *Mint {
+	cr := newCORS(config)
+	cr.scoped = true
+	mt.cors = true
+	mt.Use(cr.handle)
+	return mt
+}
+
+//CORS registers CORS middleware for the group,
+//preflight requests to routes under the group are answered by it
+//instead of CORS of application or parent groups
+func (hg *HandlersGroup) CORS(config CORSConfig) *HandlersGroup {
+	if hg == nil {
+		return hg
+	}
+	cr := newCORS(config)
+	cr.scoped, cr.group = true, hg
+	hg.cors = true
+	hg.Use(cr.handle)
+	return hg
+}
+
+//corsGroup returns innermost group of handler having CORS,
+//nil when CORS of application applies
+func (hc *HandlerContext) corsGroup() *HandlersGroup {
+	for group := hc.group; group != nil; group = group.parent {
+		if group.cors {
+			return group
+		}
+	}
+	return nil
+}
+
+//buildPreflight registers route which answers preflight requests
+//using middleware chain of application or group
+func buildPreflight(mt *Mint, group *HandlersGroup, middleware []HandlerFunc, router *mux.Router) {
+	preflight := HandlerBuilder()
+	preflight.Mint = mt
+	preflight.group = group
+	preflight.middleware = middleware
+	preflight.buildWithRoute(router.NewRoute().MatcherFunc(mt.preflightMatcher))
+}
@@ -0,0 +1,90 @@
+package mint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func corsRequest(router http.Handler, method, path, origin, requestMethod string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set(headerOrigin, origin)
+	if requestMethod != emptyString {
+		req.Header.Set(headerAccessControlRequestMethod, requestMethod)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func newCORSApp() *Mint {
+	mt := Simple()
+	mt.CORS(CORSConfig{AllowedOrigins: []string{"https://app.com"}, AllowCredentials: true})
+	mt.GET("/a", func(c *Context) { c.Status(http.StatusOK) })
+	group := mt.Group("/g").Methods(http.MethodGet)
+	group.CORS(CORSConfig{AllowedOrigins: []string{"https://only.com"}, AllowedMethods: []string{http.MethodGet}})
+	group.GET("/x", func(c *Context) { c.Status(http.StatusOK) })
+	return mt
+}
+
+func TestCORSPreflight(t *testing.T) {
+	router := newCORSApp().Build()
+	tests := []struct {
+		name   string
+		path   string
+		origin string
+		code   int
+		allow  string
+	}{
+		{"application route", "/a", "https://app.com", http.StatusNoContent, "https://app.com"},
+		{"origin of group on application route", "/a", "https://only.com", http.StatusForbidden, emptyString},
+		{"group route", "/g/x", "https://only.com", http.StatusNoContent, "https://only.com"},
+		{"origin of application on group route", "/g/x", "https://app.com", http.StatusForbidden, emptyString},
+		{"unknown origin on group route", "/g/x", "https://evil.com", http.StatusForbidden, emptyString},
+		{"unknown path", "/nonexistent", "https://app.com", http.StatusNotFound, emptyString},
+	}
+	for _, test := range tests {
+		res := corsRequest(router, http.MethodOptions, test.path, test.origin, http.MethodGet)
+		if res.Code != test.code {
+			t.Errorf("%s: status = %d, want %d", test.name, res.Code, test.code)
+		}
+		if allow := res.Header().Get(headerAccessControlAllowOrigin); allow != test.allow {
+			t.Errorf("%s: %s = %q, want %q", test.name, headerAccessControlAllowOrigin, allow, test.allow)
+		}
+	}
+	res := corsRequest(router, http.MethodOptions, "/g/x", "https://only.com", http.MethodGet)
+	if credentials := res.Header().Get(headerAccessControlAllowCredentials); credentials != emptyString {
+		t.Errorf("group preflight has %s %q of application", headerAccessControlAllowCredentials, credentials)
+	}
+}
+
+func TestCORSRequestUsesInnermostConfig(t *testing.T) {
+	router := newCORSApp().Build()
+	res := corsRequest(router, http.MethodGet, "/g/x", "https://evil.com", emptyString)
+	if res.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", res.Code, http.StatusOK)
+	}
+	if allow := res.Header().Get(headerAccessControlAllowOrigin); allow != emptyString {
+		t.Errorf("%s = %q for origin which is not allowed", headerAccessControlAllowOrigin, allow)
+	}
+	res = corsRequest(router, http.MethodGet, "/g/x", "https://app.com", emptyString)
+	if allow := res.Header().Get(headerAccessControlAllowOrigin); allow != emptyString {
+		t.Errorf("%s = %q for origin of application on group route", headerAccessControlAllowOrigin, allow)
+	}
+}
+
+func TestCORSAnyOriginWithCredentialsPanics(t *testing.T) {
+	for _, config := range []CORSConfig{
+		{AllowCredentials: true},
+		{AllowedOrigins: []string{"*"}, AllowCredentials: true},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("CORS(%+v) did not panic", config)
+				}
+			}()
+			CORS(config)
+		}()
+	}
+}
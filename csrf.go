@@ -0,0 +1,136 @@
+package mint
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+)
+
+//CSRF defaults
+const (
+	DefaultCSRFCookieName = "_csrf"
+	DefaultCSRFHeaderName = "X-CSRF-Token"
+	DefaultCSRFFormField  = "csrf_token"
+	csrfTokenLength       = 32
+)
+
+//CSRFStore keeps CSRF token of the client
+//Default store keeps the token in cookie (double submit cookie),
+//session backed store gives synchronizer token pattern
+type CSRFStore interface {
+	//Token returns saved token, empty string if there is no token
+	Token(c *Context) (string, error)
+	//SaveToken saves new token
+	SaveToken(c *Context, token string) error
+}
+
+//CSRFConfig configures CSRF middleware
+type CSRFConfig struct {
+	//HeaderName is request header containing token, default is X-CSRF-Token
+	HeaderName string
+	//FormField is form field containing token, default is csrf_token
+	FormField string
+	//Store keeps the token, default is cookie store
+	Store CSRFStore
+	//Cookie is template of cookie used by default store,
+	//default cookie name is _csrf with path "/"
+	Cookie http.Cookie
+}
+
+type cookieCSRFStore struct {
+	cookie http.Cookie
+}
+
+func (store *cookieCSRFStore) Token(c *Context) (string, error) {
+	cookie, err := c.Req.Cookie(store.cookie.Name)
+	if err != nil {
+		return emptyString, nil
+	}
+	return cookie.Value, nil
+}
+
+func (store *cookieCSRFStore) SaveToken(c *Context, token string) error {
+	cookie := store.cookie
+	cookie.Value = token
+	http.SetCookie(c.Res, &cookie)
+	return nil
+}
+
+//CSRF creates CSRF protection middleware
+//Safe methods are exempted, other requests must send the token
+//in header or form field, token is available using c.CSRFToken()
+func CSRF(config CSRFConfig) HandlerFunc {
+	if config.HeaderName == emptyString {
+		config.HeaderName = DefaultCSRFHeaderName
+	}
+	if config.FormField == emptyString {
+		config.FormField = DefaultCSRFFormField
+	}
+	store := config.Store
+	if store == nil {
+		cookie := config.Cookie
+		if cookie.Name == emptyString {
+			cookie.Name = DefaultCSRFCookieName
+		}
+		if cookie.Path == emptyString {
+			cookie.Path = "/"
+		}
+		if cookie.SameSite == 0 {
+			cookie.SameSite = http.SameSiteLaxMode
+		}
+		cookie.HttpOnly = true
+		store = &cookieCSRFStore{cookie: cookie}
+	}
+	return func(c *Context) {
+		token, err := store.Token(c)
+		if err != nil {
+			c.Error(err)
+			ErrorMessage(c, http.StatusInternalServerError, "Internal server error")
+			return
+		}
+		if len(token) == 0 {
+			if token, err = newCSRFToken(); err == nil {
+				err = store.SaveToken(c, token)
+			}
+			if err != nil {
+				c.Error(err)
+				ErrorMessage(c, http.StatusInternalServerError, "Internal server error")
+				return
+			}
+		}
+		c.csrfToken = token
+		if !isSafeMethod(c.Req.Method) {
+			submitted := c.GetHeader(config.HeaderName)
+			if submitted == emptyString {
+				submitted = c.Req.PostFormValue(config.FormField)
+			}
+			if submitted == emptyString || subtle.ConstantTimeCompare([]byte(submitted), []byte(token)) != 1 {
+				ErrorMessage(c, http.StatusForbidden, "Invalid CSRF token")
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+func newCSRFToken() (string, error) {
+	token := make([]byte, csrfTokenLength)
+	if _, err := rand.Read(token); err != nil {
+		return emptyString, err
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+//CSRFToken returns CSRF token of the request to be rendered in templates
+func (c *Context) CSRFToken() string {
+	return c.csrfToken
+}
@@ -0,0 +1,87 @@
+package mint
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"time"
+)
+
+//processStart is used to report uptime
+var processStart = time.Now()
+
+//RuntimeInfo is runtime information of application served by debug endpoints
+type RuntimeInfo struct {
+	GoVersion    string    `json:"go_version"`
+	GOOS         string    `json:"goos"`
+	GOARCH       string    `json:"goarch"`
+	NumCPU       int       `json:"num_cpu"`
+	GOMAXPROCS   int       `json:"gomaxprocs"`
+	Goroutines   int       `json:"goroutines"`
+	StartTime    time.Time `json:"start_time"`
+	Uptime       string    `json:"uptime"`
+	HeapAlloc    uint64    `json:"heap_alloc_bytes"`
+	HeapInuse    uint64    `json:"heap_inuse_bytes"`
+	Sys          uint64    `json:"sys_bytes"`
+	NumGC        uint32    `json:"num_gc"`
+	PauseTotalNs uint64    `json:"gc_pause_total_ns"`
+	Pools        PoolStats `json:"pools"`
+}
+
+//RuntimeInfo returns runtime information of application
+func (mt *Mint) RuntimeInfo() RuntimeInfo {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return RuntimeInfo{
+		GoVersion:    runtime.Version(),
+		GOOS:         runtime.GOOS,
+		GOARCH:       runtime.GOARCH,
+		NumCPU:       runtime.NumCPU(),
+		GOMAXPROCS:   runtime.GOMAXPROCS(0),
+		Goroutines:   runtime.NumGoroutine(),
+		StartTime:    processStart,
+		Uptime:       time.Since(processStart).Round(time.Second).String(),
+		HeapAlloc:    stats.HeapAlloc,
+		HeapInuse:    stats.HeapInuse,
+		Sys:          stats.Sys,
+		NumGC:        stats.NumGC,
+		PauseTotalNs: stats.PauseTotalNs,
+		Pools:        mt.PoolStats(),
+	}
+}
+
+//EnableDebug mounts debug endpoints under prefix such as /debug, auth and more
+//run before every endpoint. Debug endpoints expose internals of application,
+//so it panics when auth is nil
+//
+//	{prefix}/pprof/       pprof index and profiles such as heap, goroutine and allocs
+//	{prefix}/vars         expvar variables
+//	{prefix}/routes       route table
+//	{prefix}/runtime      runtime information
+func (mt *Mint) EnableDebug(prefix string, auth HandlerFunc, more ...HandlerFunc) *HandlersGroup {
+	if auth == nil {
+		panic("mint: debug endpoints require auth middleware")
+	}
+	group := NewGroup(prefix)
+	group.Use(auth)
+	group.Use(more...)
+	group.GET("/pprof/", WrapF(pprof.Index)).Undocumented()
+	group.GET("/pprof/cmdline", WrapF(pprof.Cmdline)).Undocumented()
+	group.GET("/pprof/profile", WrapF(pprof.Profile)).Undocumented()
+	group.Match([]string{http.MethodGet, http.MethodPost}, "/pprof/symbol", WrapF(pprof.Symbol)).Undocumented()
+	group.GET("/pprof/trace", WrapF(pprof.Trace)).Undocumented()
+	group.GET("/pprof/{profile}", func(c *Context) {
+		profile, _ := c.Param("profile")
+		WrapH(pprof.Handler(profile))(c)
+	}).Undocumented()
+	group.GET("/vars", WrapH(expvar.Handler())).Undocumented()
+	group.GET("/routes", func(c *Context) {
+		c.JSON(http.StatusOK, mt.Routes())
+	}).Undocumented()
+	group.GET("/runtime", func(c *Context) {
+		c.JSON(http.StatusOK, mt.RuntimeInfo())
+	}).Undocumented()
+	mt.AddGroup(group)
+	return group
+}
@@ -2,9 +2,12 @@ package mint
 
 import (
 	"net/http"
+	"strings"
 	"time"
 )
 
+const headerAllow = "Allow"
+
 //LoggerMW logger middleware
 func loggerMW(c *Context) {
 	start := time.Now()
@@ -18,8 +21,23 @@ func loggerMW(c *Context) {
 	log.ClientIP = c.ClientIP()
 	log.BodySize = c.size
 	log.Path = path
+	log.UserName = c.UserName()
 	log.Errors = c.errors
-	log.Print()
+	log.Fprint(c.logWriter())
+}
+
+//allowMW sets Allow header of method not allowed response
+//and answers OPTIONS request with allowed methods
+func allowMW(c *Context) {
+	allowed := c.HandlerContext.Mint.allowedMethods(c.Req)
+	if len(allowed) > 0 {
+		c.Res.Header().Set(headerAllow, strings.Join(allowed, ", "))
+		if c.Req.Method == http.MethodOptions {
+			c.Status(http.StatusNoContent)
+			return
+		}
+	}
+	c.Next()
 }
 
 func notFoundHandler(c *Context) {
@@ -2,6 +2,7 @@ package mint
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/gorilla/mux"
 )
@@ -9,6 +10,19 @@ import (
 //HandlerFunc handles requests for an URL
 type HandlerFunc func(*Context)
 
+//anyMethods are methods registered by Any
+var anyMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodOptions,
+	http.MethodConnect,
+	http.MethodTrace,
+}
+
 //Handlers chain of Handler
 type Handlers []*HandlerContext
 
@@ -24,8 +38,15 @@ type HandlerContext struct {
 	headers    []string
 	queries    []string
 	path       string
+	host       string
 	name       string
 	compressed bool
+	route      *mux.Route
+	doc        *apiDoc
+	group      *HandlersGroup
+	autoHead   bool
+	converters map[string]*converter
+	template   string
 }
 
 //HandlerBuilder new handerContext
@@ -37,15 +58,30 @@ func (hc *HandlerContext) build(router *mux.Router) {
 	if hc == nil {
 		return
 	}
-	hc.handlers = append(hc.middleware, hc.handlers...)
+	hc.handlers = chain(hc.middleware, hc.handlers)
 	hc.count = len(hc.handlers)
-	route := router.Handle(hc.path, hc)
-	addFilters(hc, route)
+	hc.path, hc.converters = hc.Mint.applyConverters(hc.path, hc.converters)
+	hc.route = router.Handle(hc.path, hc)
+	addFilters(hc, hc.route)
+	hc.template = hc.routeTemplate()
+	hc.Mint.routes = append(hc.Mint.routes, hc)
 }
 
 func addFilters(hc *HandlerContext, route *mux.Route) {
+	if len(hc.host) > 0 {
+		route.Host(hc.host)
+	}
 	if len(hc.methods) > 0 {
-		route.Methods(hc.methods...)
+		methods := hc.methods
+		//GET handlers answer HEAD requests with body suppressed
+		if containsMethod(methods, http.MethodGet) && !containsMethod(methods, http.MethodHead) {
+			methods = append(append([]string(nil), methods...), http.MethodHead)
+			hc.autoHead = true
+		}
+		route.Methods(methods...)
+	} else if hc.group != nil && hc.group.autoHead() {
+		//HEAD requests reach handler through GET groups
+		hc.autoHead = true
 	}
 	if len(hc.schemes) > 0 {
 		route.Schemes(hc.schemes...)
@@ -64,10 +100,11 @@ func (hc *HandlerContext) buildWithRoute(route *mux.Route) {
 	if hc == nil {
 		return
 	}
-	hc.handlers = append(hc.middleware, hc.handlers...)
+	hc.handlers = chain(hc.middleware, hc.handlers)
 	hc.count = len(hc.handlers)
-	route1 := route.Handler(hc)
-	addFilters(hc, route1)
+	hc.route = route.Handler(hc)
+	addFilters(hc, hc.route)
+	hc.template = hc.routeTemplate()
 }
 
 //Methods #
@@ -75,6 +112,7 @@ func (hc *HandlerContext) Methods(methods ...string) *HandlerContext {
 	if hc == nil {
 		return hc
 	}
+	hc.checkNotBuilt()
 	hc.methods = append(hc.methods, methods...)
 	return hc
 }
@@ -84,6 +122,7 @@ func (hc *HandlerContext) Handle(handlers ...HandlerFunc) *HandlerContext {
 	if hc == nil {
 		return hc
 	}
+	hc.checkNotBuilt()
 	hc.handlers = append(hc.handlers, handlers...)
 	return hc
 }
@@ -93,6 +132,7 @@ func (hc *HandlerContext) Schemes(schemes ...string) *HandlerContext {
 	if hc == nil {
 		return hc
 	}
+	hc.checkNotBuilt()
 	hc.schemes = append(hc.schemes, schemes...)
 	return hc
 }
@@ -102,6 +142,7 @@ func (hc *HandlerContext) Headers(headers ...string) *HandlerContext {
 	if hc == nil {
 		return hc
 	}
+	hc.checkNotBuilt()
 	hc.headers = append(hc.headers, headers...)
 	return hc
 }
@@ -111,6 +152,7 @@ func (hc *HandlerContext) Queries(queries ...string) *HandlerContext {
 	if hc == nil {
 		return hc
 	}
+	hc.checkNotBuilt()
 	hc.queries = append(hc.queries, queries...)
 	return hc
 }
@@ -120,15 +162,28 @@ func (hc *HandlerContext) Path(path string) *HandlerContext {
 	if hc == nil {
 		return hc
 	}
+	hc.checkNotBuilt()
 	hc.path = path
 	return hc
 }
 
+//Host filters requests by host template such as {tenant}.example.com,
+//host variables are available using c.Param
+func (hc *HandlerContext) Host(host string) *HandlerContext {
+	if hc == nil {
+		return hc
+	}
+	hc.checkNotBuilt()
+	hc.host = host
+	return hc
+}
+
 //Name #
 func (hc *HandlerContext) Name(name string) *HandlerContext {
 	if hc == nil {
 		return hc
 	}
+	hc.checkNotBuilt()
 	hc.name = name
 	return hc
 }
@@ -138,20 +193,92 @@ func (hc *HandlerContext) Compressed(isCompressed bool) *HandlerContext {
 	if hc == nil {
 		return hc
 	}
+	hc.checkNotBuilt()
 	hc.compressed = isCompressed
 	return hc
 }
 
+func (hc *HandlerContext) clone() *HandlerContext {
+	if hc == nil {
+		return nil
+	}
+	copied := *hc
+	return &copied
+}
+
+func cloneHandlers(handlers []*HandlerContext) []*HandlerContext {
+	copied := make([]*HandlerContext, len(handlers))
+	for index, hc := range handlers {
+		copied[index] = hc.clone()
+	}
+	return copied
+}
+
+//checkNotBuilt panics when handler is changed after application is built,
+//Mint of handler is set while application is built
+func (hc *HandlerContext) checkNotBuilt() {
+	if hc.Mint != nil {
+		hc.Mint.checkNotBuilt()
+	}
+}
+
 //ServeHTTP #
 func (hc *HandlerContext) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	c := hc.Mint.contextPool.Get().(*Context)
 	c.Reset()
+	//hooks run and scope is closed even when handler panics
+	defer func() {
+		for _, hook := range hc.Mint.afterRequest {
+			hook(c)
+		}
+		if capture := requestCapture(req); capture != nil {
+			capture.record(c.errors)
+		}
+		c.closeScope()
+		hc.Mint.contextPool.Put(c)
+	}()
 	c.HandlerContext = hc
+	c.scope = newServiceScope()
 	c.params = mux.Vars(req)
+	c.convertParams()
 	c.Req = req
 	c.Res = w
+	if hc.autoHead && req.Method == http.MethodHead {
+		c.Res = headWriter{w}
+	}
 	c.Next()
-	hc.Mint.contextPool.Put(c)
+}
+
+//chain joins handlers into new slice,
+//so chains sharing same middleware do not overwrite each other
+func chain(handlers ...[]HandlerFunc) []HandlerFunc {
+	count := 0
+	for _, h := range handlers {
+		count += len(h)
+	}
+	joined := make([]HandlerFunc, 0, count)
+	for _, h := range handlers {
+		joined = append(joined, h...)
+	}
+	return joined
+}
+
+//headWriter discards body of response to HEAD request
+type headWriter struct {
+	http.ResponseWriter
+}
+
+func (hw headWriter) Write(data []byte) (int, error) {
+	return len(data), nil
+}
+
+func containsMethod(methods []string, method string) bool {
+	for _, m := range methods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
 }
 
 //Use registers middleware
@@ -159,6 +286,7 @@ func (hc *HandlerContext) Use(handler ...HandlerFunc) *HandlerContext {
 	if hc == nil {
 		return hc
 	}
+	hc.checkNotBuilt()
 	hc.middleware = append(hc.middleware, handler...)
 	return hc
 }
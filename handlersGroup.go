@@ -1,6 +1,8 @@
 package mint
 
 import (
+	"net/http"
+
 	"github.com/gorilla/mux"
 )
 
@@ -10,40 +12,148 @@ type HandlersGroup struct {
 	middleware    []HandlerFunc
 	basePath      string
 	prefixHandler *HandlerContext
-	router        *mux.Router
+	parent        *HandlersGroup
 	handlersGroup []*HandlersGroup
 	handlers      []*HandlerContext
+	cors          bool
+	methods       []string
+	schemes       []string
+	headers       []string
+	queries       []string
+	host          string
+	matcher       mux.MatcherFunc
+	converters    map[string]*converter
 }
 
 func (hg *HandlersGroup) build(parentRouter *mux.Router) {
 	if hg == nil {
 		return
 	}
-	route := parentRouter.PathPrefix(hg.basePath)
+	var route *mux.Route
+	hg.basePath, hg.converters = hg.mint.applyConverters(hg.basePath, hg.converters)
+	if len(hg.basePath) > 0 {
+		route = parentRouter.PathPrefix(hg.basePath)
+	} else {
+		route = parentRouter.NewRoute()
+	}
+	hg.addFilters(route)
 	if hg.prefixHandler != nil {
 		hg.prefixHandler.Mint = hg.mint
-		hg.prefixHandler.middleware = append(hg.middleware, hg.prefixHandler.middleware...)
+		hg.prefixHandler.group = hg
+		hg.prefixHandler.converters = inheritConverters(hg.converters, hg.prefixHandler.converters)
+		hg.prefixHandler.middleware = chain(hg.middleware, hg.prefixHandler.middleware)
 		hg.prefixHandler.buildWithRoute(route)
+		hg.mint.routes = append(hg.mint.routes, hg.prefixHandler)
 		return
 	}
 	subrouter := route.Subrouter()
+	if hg.cors {
+		buildPreflight(hg.mint, hg, hg.middleware, subrouter)
+	}
 	for _, handler := range hg.handlers {
 		handler.Mint = hg.mint
-		handler.middleware = append(hg.middleware, handler.middleware...)
+		handler.group = hg
+		handler.converters = inheritConverters(hg.converters, handler.converters)
+		handler.middleware = chain(hg.middleware, handler.middleware)
 		handler.build(subrouter)
 	}
 	for _, group := range hg.handlersGroup {
 		group.mint = hg.mint
-		group.middleware = append(hg.middleware, group.middleware...)
+		group.parent = hg
+		group.converters = inheritConverters(hg.converters, group.converters)
+		group.middleware = chain(hg.middleware, group.middleware)
 		group.build(subrouter)
 	}
 }
 
+//addFilters adds filters of the group to its prefix route,
+//they must be added before creating subrouter so that nested routes inherit them
+func (hg *HandlersGroup) addFilters(route *mux.Route) {
+	if len(hg.host) > 0 {
+		route.Host(hg.host)
+	}
+	if len(hg.methods) > 0 {
+		methods := hg.filterMethods()
+		//preflight requests must reach preflight route of the group or its subgroups
+		if hg.hasCORS() && !containsMethod(methods, http.MethodOptions) {
+			methods = append(append([]string(nil), methods...), http.MethodOptions)
+		}
+		route.Methods(methods...)
+	}
+	if len(hg.schemes) > 0 {
+		route.Schemes(hg.schemes...)
+	}
+	if len(hg.headers) > 0 {
+		route.Headers(hg.headers...)
+	}
+	if len(hg.queries) > 0 {
+		route.Queries(hg.queries...)
+	}
+	if hg.matcher != nil {
+		route.MatcherFunc(hg.matcher)
+	}
+}
+
+//filterMethods returns methods of the group, GET groups answer HEAD
+//requests with body suppressed same as GET handlers
+func (hg *HandlersGroup) filterMethods() []string {
+	if containsMethod(hg.methods, http.MethodGet) && !containsMethod(hg.methods, http.MethodHead) {
+		return append(append([]string(nil), hg.methods...), http.MethodHead)
+	}
+	return hg.methods
+}
+
+//autoHead checks whether HEAD is allowed by group or its parents only
+//because they allow GET
+func (hg *HandlersGroup) autoHead() bool {
+	for group := hg; group != nil; group = group.parent {
+		if containsMethod(group.methods, http.MethodGet) && !containsMethod(group.methods, http.MethodHead) {
+			return true
+		}
+	}
+	return false
+}
+
+//clone copies group with its handlers and subgroups, groups maps groups to their copies
+func (hg *HandlersGroup) clone(groups map[*HandlersGroup]*HandlersGroup) *HandlersGroup {
+	copied := *hg
+	copied.prefixHandler = hg.prefixHandler.clone()
+	copied.handlers = cloneHandlers(hg.handlers)
+	copied.handlersGroup = make([]*HandlersGroup, len(hg.handlersGroup))
+	for index, group := range hg.handlersGroup {
+		copied.handlersGroup[index] = group.clone(groups)
+	}
+	groups[hg] = &copied
+	return &copied
+}
+
+//hasCORS checks whether group or one of its subgroups has CORS
+func (hg *HandlersGroup) hasCORS() bool {
+	if hg.cors {
+		return true
+	}
+	for _, group := range hg.handlersGroup {
+		if group.hasCORS() {
+			return true
+		}
+	}
+	return false
+}
+
+//checkNotBuilt panics when group is changed after application is built,
+//mint of group is set while application is built
+func (hg *HandlersGroup) checkNotBuilt() {
+	if hg.mint != nil {
+		hg.mint.checkNotBuilt()
+	}
+}
+
 //PrefixHandler registers handler for prefix request
 func (hg *HandlersGroup) PrefixHandler(hc *HandlerContext) {
 	if hg == nil {
 		return
 	}
+	hg.checkNotBuilt()
 	hg.prefixHandler = hc
 }
 
@@ -59,6 +169,7 @@ func (hg *HandlersGroup) AddGroup(newhg *HandlersGroup) *HandlersGroup {
 	if hg == nil {
 		return hg
 	}
+	hg.checkNotBuilt()
 	hg.handlersGroup = append(hg.handlersGroup, newhg)
 	return hg
 }
@@ -96,6 +207,7 @@ func (hg *HandlersGroup) Group(pathPrefix string) *HandlersGroup {
 	if hg == nil {
 		return hg
 	}
+	hg.checkNotBuilt()
 	handlersGroup := new(HandlersGroup)
 	handlersGroup.basePath = pathPrefix
 	hg.handlersGroup = append(hg.handlersGroup, handlersGroup)
@@ -107,16 +219,38 @@ func (hg *HandlersGroup) Use(handler ...HandlerFunc) *HandlersGroup {
 	if hg == nil {
 		return hg
 	}
+	hg.checkNotBuilt()
 	hg.middleware = append(hg.middleware, handler...)
 	return hg
 }
 
+//Methods filters requests to the group by methods
+func (hg *HandlersGroup) Methods(methods ...string) *HandlersGroup {
+	if hg == nil {
+		return hg
+	}
+	hg.checkNotBuilt()
+	hg.methods = append(hg.methods, methods...)
+	return hg
+}
+
+//Host filters requests to the group by host template
+func (hg *HandlersGroup) Host(host string) *HandlersGroup {
+	if hg == nil {
+		return hg
+	}
+	hg.checkNotBuilt()
+	hg.host = host
+	return hg
+}
+
 //Schemes #
 func (hg *HandlersGroup) Schemes(schemes ...string) *HandlersGroup {
 	if hg == nil {
 		return hg
 	}
-	hg.router.Schemes(schemes...)
+	hg.checkNotBuilt()
+	hg.schemes = append(hg.schemes, schemes...)
 	return hg
 }
 
@@ -125,7 +259,8 @@ func (hg *HandlersGroup) Headers(headers ...string) *HandlersGroup {
 	if hg == nil {
 		return hg
 	}
-	hg.router.Headers(headers...)
+	hg.checkNotBuilt()
+	hg.headers = append(hg.headers, headers...)
 	return hg
 }
 
@@ -134,28 +269,78 @@ func (hg *HandlersGroup) Queries(queries ...string) *HandlersGroup {
 	if hg == nil {
 		return hg
 	}
-	hg.router.Queries(queries...)
+	hg.checkNotBuilt()
+	hg.queries = append(hg.queries, queries...)
 	return hg
 }
 
 //SimpleHandler registers simple handler
 func (hg *HandlersGroup) SimpleHandler(path string, method string, handler ...HandlerFunc) *HandlerContext {
+	return hg.Match([]string{method}, path, handler...)
+}
+
+//Match registers handler for multiple methods
+func (hg *HandlersGroup) Match(methods []string, path string, handler ...HandlerFunc) *HandlerContext {
 	if hg == nil {
 		return nil
 	}
+	hg.checkNotBuilt()
 	hc := HandlerBuilder()
-	hc.Methods(method)
+	hc.Methods(methods...)
 	hc.Handle(handler...)
 	hc.Path(path)
 	hg.handlers = append(hg.handlers, hc)
 	return hc
 }
 
+//GET registers simple GET handler
+func (hg *HandlersGroup) GET(path string, handler ...HandlerFunc) *HandlerContext {
+	return hg.SimpleHandler(path, http.MethodGet, handler...)
+}
+
+//POST registers simple POST handler
+func (hg *HandlersGroup) POST(path string, handler ...HandlerFunc) *HandlerContext {
+	return hg.SimpleHandler(path, http.MethodPost, handler...)
+}
+
+//PUT registers simple PUT handler
+func (hg *HandlersGroup) PUT(path string, handler ...HandlerFunc) *HandlerContext {
+	return hg.SimpleHandler(path, http.MethodPut, handler...)
+}
+
+//DELETE registers simple DELETE handler
+func (hg *HandlersGroup) DELETE(path string, handler ...HandlerFunc) *HandlerContext {
+	return hg.SimpleHandler(path, http.MethodDelete, handler...)
+}
+
+//PATCH registers simple PATCH handler
+func (hg *HandlersGroup) PATCH(path string, handler ...HandlerFunc) *HandlerContext {
+	return hg.SimpleHandler(path, http.MethodPatch, handler...)
+}
+
+//HEAD registers simple HEAD handler
+//GET handlers answer HEAD requests automatically, register HEAD handler
+//before GET handler of the same path to override it
+func (hg *HandlersGroup) HEAD(path string, handler ...HandlerFunc) *HandlerContext {
+	return hg.SimpleHandler(path, http.MethodHead, handler...)
+}
+
+//OPTIONS registers simple OPTIONS handler
+func (hg *HandlersGroup) OPTIONS(path string, handler ...HandlerFunc) *HandlerContext {
+	return hg.SimpleHandler(path, http.MethodOptions, handler...)
+}
+
+//Any registers handler for all methods
+func (hg *HandlersGroup) Any(path string, handler ...HandlerFunc) *HandlerContext {
+	return hg.Match(anyMethods, path, handler...)
+}
+
 //Handler registers new Handler
 func (hg *HandlersGroup) Handler(hc *HandlerContext) *HandlersGroup {
 	if hg == nil {
 		return nil
 	}
+	hg.checkNotBuilt()
 	hg.handlers = append(hg.handlers, hc)
 	return hg
 }
@@ -0,0 +1,292 @@
+package mint
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+//Health statuses
+const (
+	HealthStatusOK   = "ok"
+	HealthStatusFail = "fail"
+)
+
+//Default health settings
+const (
+	DefaultLivenessPath    = "/healthz"
+	DefaultReadinessPath   = "/readyz"
+	DefaultHealthTimeout   = 5 * time.Second
+	healthShuttingDownName = "shutdown"
+)
+
+//ErrShuttingDown is reported by readiness while application is shutting down
+var ErrShuttingDown = errors.New("mint: application is shutting down")
+
+//ErrHealthTimeout is reported when health check does not finish in its timeout
+var ErrHealthTimeout = errors.New("mint: health check timed out")
+
+//HealthCheck checks health of a dependency, it should return when ctx is done
+type HealthCheck func(ctx context.Context) error
+
+//HealthResult is aggregated result of health checks
+type HealthResult struct {
+	Status string                        `json:"status"`
+	Checks map[string]*HealthCheckResult `json:"checks,omitempty"`
+	//Time is when checks were run, result is cached until Time + cache interval
+	Time time.Time `json:"time"`
+}
+
+//HealthCheckResult is result of single health check
+type HealthCheckResult struct {
+	Status     string  `json:"status"`
+	DurationMS float64 `json:"duration_ms"`
+	Error      string  `json:"error,omitempty"`
+}
+
+//Health serves liveness and readiness endpoints of application.
+//Liveness fails when application must be restarted, readiness fails when
+//application cannot serve traffic, including while it is shutting down
+type Health struct {
+	mint          *Mint
+	mutex         sync.Mutex
+	liveness      *healthProbe
+	readiness     *healthProbe
+	cacheFor      time.Duration
+	shutdownDelay time.Duration
+}
+
+//healthProbe is set of checks served at a path
+type healthProbe struct {
+	handler *HandlerContext
+	mutex   sync.Mutex
+	checks  []*namedCheck
+	result  *HealthResult
+	ok      bool
+	//running is closed when run in progress finishes, concurrent probes wait for it
+	running chan struct{}
+}
+
+type namedCheck struct {
+	name    string
+	timeout time.Duration
+	check   HealthCheck
+}
+
+//Health returns health endpoints of application, /healthz and /readyz
+//are registered when it is called first time
+func (mt *Mint) Health() *Health {
+	if mt.health != nil {
+		return mt.health
+	}
+	health := &Health{mint: mt, liveness: new(healthProbe), readiness: new(healthProbe)}
+	health.liveness.handler = mt.GET(DefaultLivenessPath, health.handler(health.liveness, false)).Undocumented()
+	health.readiness.handler = mt.GET(DefaultReadinessPath, health.handler(health.readiness, true)).Undocumented()
+	mt.health = health
+	return health
+}
+
+//Paths sets paths of liveness and readiness endpoints, empty path is not changed
+func (h *Health) Paths(liveness, readiness string) *Health {
+	if liveness != emptyString {
+		h.liveness.handler.Path(liveness)
+	}
+	if readiness != emptyString {
+		h.readiness.handler.Path(readiness)
+	}
+	return h
+}
+
+//CacheFor caches results of checks for interval, so frequent probes do not
+//overload dependencies. Results are not cached by default
+func (h *Health) CacheFor(interval time.Duration) *Health {
+	h.mutex.Lock()
+	h.cacheFor = interval
+	h.mutex.Unlock()
+	return h
+}
+
+//ShutdownDelay is time Shutdown waits after readiness starts failing before
+//server stops accepting connections, so load balancers can stop sending traffic
+func (h *Health) ShutdownDelay(delay time.Duration) *Health {
+	h.mutex.Lock()
+	h.shutdownDelay = delay
+	h.mutex.Unlock()
+	return h
+}
+
+//Liveness registers check of liveness endpoint, zero timeout is DefaultHealthTimeout
+func (h *Health) Liveness(name string, timeout time.Duration, check HealthCheck) *Health {
+	h.liveness.add(name, timeout, check)
+	return h
+}
+
+//Readiness registers check of readiness endpoint, zero timeout is DefaultHealthTimeout
+func (h *Health) Readiness(name string, timeout time.Duration, check HealthCheck) *Health {
+	h.readiness.add(name, timeout, check)
+	return h
+}
+
+func (probe *healthProbe) add(name string, timeout time.Duration, check HealthCheck) {
+	if timeout <= 0 {
+		timeout = DefaultHealthTimeout
+	}
+	probe.mutex.Lock()
+	probe.checks = append(probe.checks, &namedCheck{name: name, timeout: timeout, check: check})
+	probe.result = nil
+	probe.mutex.Unlock()
+}
+
+//CheckLiveness runs liveness checks, it returns failed result when ctx is done before checks finish
+func (h *Health) CheckLiveness(ctx context.Context) (*HealthResult, bool) {
+	return h.check(ctx, h.liveness, false)
+}
+
+//CheckReadiness runs readiness checks, readiness fails while application is shutting down
+func (h *Health) CheckReadiness(ctx context.Context) (*HealthResult, bool) {
+	return h.check(ctx, h.readiness, true)
+}
+
+func (h *Health) check(ctx context.Context, probe *healthProbe, readiness bool) (*HealthResult, bool) {
+	if readiness && h.mint.ShuttingDown() {
+		result := &HealthResult{
+			Status: HealthStatusFail,
+			Checks: map[string]*HealthCheckResult{
+				healthShuttingDownName: {Status: HealthStatusFail, Error: ErrShuttingDown.Error()},
+			},
+			Time: time.Now(),
+		}
+		return result, false
+	}
+	h.mutex.Lock()
+	cacheFor := h.cacheFor
+	h.mutex.Unlock()
+	probe.mutex.Lock()
+	if probe.result != nil && time.Since(probe.result.Time) < cacheFor {
+		defer probe.mutex.Unlock()
+		return probe.result, probe.ok
+	}
+	running := probe.running
+	if running == nil {
+		running = make(chan struct{})
+		probe.running = running
+		go probe.run(append([]*namedCheck(nil), probe.checks...), running)
+	}
+	probe.mutex.Unlock()
+	select {
+	case <-running:
+	case <-ctx.Done():
+		return &HealthResult{Status: HealthStatusFail, Time: time.Now()}, false
+	}
+	probe.mutex.Lock()
+	defer probe.mutex.Unlock()
+	return probe.result, probe.ok
+}
+
+//run runs checks and stores result, checks are not bound to context of
+//probe starting the run as result is shared, they are limited by their timeouts
+func (probe *healthProbe) run(checks []*namedCheck, running chan struct{}) {
+	result, ok := runChecks(context.Background(), checks)
+	probe.mutex.Lock()
+	probe.result, probe.ok = result, ok
+	probe.running = nil
+	probe.mutex.Unlock()
+	close(running)
+}
+
+//runChecks runs checks concurrently
+func runChecks(ctx context.Context, checks []*namedCheck) (*HealthResult, bool) {
+	result := &HealthResult{
+		Status: HealthStatusOK,
+		Checks: make(map[string]*HealthCheckResult, len(checks)),
+		Time:   time.Now(),
+	}
+	results := make([]*HealthCheckResult, len(checks))
+	var wg sync.WaitGroup
+	for index, check := range checks {
+		wg.Add(1)
+		go func(index int, check *namedCheck) {
+			defer wg.Done()
+			results[index] = check.run(ctx)
+		}(index, check)
+	}
+	wg.Wait()
+	ok := true
+	for index, check := range checks {
+		result.Checks[check.name] = results[index]
+		if results[index].Status != HealthStatusOK {
+			ok = false
+		}
+	}
+	if !ok {
+		result.Status = HealthStatusFail
+	}
+	return result, ok
+}
+
+func (nc *namedCheck) run(ctx context.Context) *HealthCheckResult {
+	ctx, cancel := context.WithTimeout(ctx, nc.timeout)
+	defer cancel()
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- errors.New("mint: health check panicked")
+			}
+		}()
+		done <- nc.check(ctx)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		//check ignoring ctx is abandoned
+		err = ErrHealthTimeout
+	}
+	result := &HealthCheckResult{
+		Status:     HealthStatusOK,
+		DurationMS: float64(time.Since(start)) / float64(time.Millisecond),
+	}
+	if err != nil {
+		result.Status = HealthStatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
+
+func (h *Health) handler(probe *healthProbe, readiness bool) HandlerFunc {
+	return func(c *Context) {
+		result, ok := h.check(c.Req.Context(), probe, readiness)
+		c.Res.Header().Set("Cache-Control", "no-store")
+		if ok {
+			c.JSON(http.StatusOK, result)
+			return
+		}
+		c.JSON(http.StatusServiceUnavailable, result)
+	}
+}
+
+//ShuttingDown checks whether Shutdown has been called
+func (mt *Mint) ShuttingDown() bool {
+	return atomic.LoadInt32(&mt.shuttingDown) == 1
+}
+
+//drain waits for shutdown delay of health, so readiness failure is noticed
+func (h *Health) drain(ctx context.Context) {
+	h.mutex.Lock()
+	delay := h.shutdownDelay
+	h.mutex.Unlock()
+	if delay <= 0 {
+		return
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,239 @@
+package mint
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+//JWKS defaults
+const (
+	DefaultJWKSRefreshInterval    = time.Hour
+	DefaultJWKSMinRefreshInterval = time.Minute
+)
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	K   string `json:"k"`
+}
+
+type jwksKey struct {
+	alg string
+	key interface{}
+}
+
+//JWKS is KeySet loaded from JSON Web Key Set document
+//keys are cached and reloaded after refresh interval or when token has unknown kid,
+//so rotated keys are picked up. Key set is loaded by one request at a time
+type JWKS struct {
+	mutex              sync.RWMutex
+	refreshMutex       sync.Mutex
+	load               func() ([]byte, error)
+	keys               map[string]jwksKey
+	fetched            time.Time
+	RefreshInterval    time.Duration
+	MinRefreshInterval time.Duration
+}
+
+//NewJWKSFromFile creates JWKS loaded from local file
+func NewJWKSFromFile(path string) *JWKS {
+	return newJWKS(func() ([]byte, error) {
+		return ioutil.ReadFile(path)
+	})
+}
+
+//NewJWKSFromURL creates JWKS loaded from URL
+func NewJWKSFromURL(url string) *JWKS {
+	client := &http.Client{Timeout: 10 * time.Second}
+	return newJWKS(func() ([]byte, error) {
+		res, err := client.Get(url)
+		if err != nil {
+			return nil, err
+		}
+		defer res.Body.Close()
+		if res.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("mint: fetching JWKS from %s failed with status %d", url, res.StatusCode)
+		}
+		return ioutil.ReadAll(res.Body)
+	})
+}
+
+func newJWKS(load func() ([]byte, error)) *JWKS {
+	return &JWKS{
+		load:               load,
+		RefreshInterval:    DefaultJWKSRefreshInterval,
+		MinRefreshInterval: DefaultJWKSMinRefreshInterval,
+	}
+}
+
+//Key returns key for kid, reloading key set if needed
+func (ks *JWKS) Key(kid string, alg string) (interface{}, error) {
+	now := time.Now()
+	ks.mutex.RLock()
+	key, ok := ks.lookup(kid, alg)
+	fetched := ks.fetched
+	stale := now.Sub(fetched) >= ks.RefreshInterval
+	canRefresh := fetched.IsZero() || now.Sub(fetched) >= ks.MinRefreshInterval
+	ks.mutex.RUnlock()
+	if ok && !stale {
+		return key, nil
+	}
+	if canRefresh {
+		if err := ks.refreshSince(fetched); err != nil && !ok {
+			return nil, err
+		}
+		ks.mutex.RLock()
+		key, ok = ks.lookup(kid, alg)
+		ks.mutex.RUnlock()
+	}
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return key, nil
+}
+
+func (ks *JWKS) lookup(kid string, alg string) (interface{}, bool) {
+	if kid != emptyString {
+		key, ok := ks.keys[kid]
+		if !ok || (key.alg != emptyString && key.alg != alg) {
+			return nil, false
+		}
+		return key.key, true
+	}
+	//token without kid can be verified only when there is single key
+	if len(ks.keys) == 1 {
+		for _, key := range ks.keys {
+			return key.key, key.alg == emptyString || key.alg == alg
+		}
+	}
+	return nil, false
+}
+
+//Refresh reloads the key set
+func (ks *JWKS) Refresh() error {
+	ks.refreshMutex.Lock()
+	defer ks.refreshMutex.Unlock()
+	return ks.refresh()
+}
+
+//refreshSince reloads the key set unless it was reloaded after fetched,
+//so concurrent requests with unknown kid cause single load
+func (ks *JWKS) refreshSince(fetched time.Time) error {
+	ks.refreshMutex.Lock()
+	defer ks.refreshMutex.Unlock()
+	ks.mutex.RLock()
+	reloaded := !ks.fetched.Equal(fetched)
+	ks.mutex.RUnlock()
+	if reloaded {
+		return nil
+	}
+	return ks.refresh()
+}
+
+func (ks *JWKS) refresh() error {
+	data, err := ks.load()
+	ks.mutex.Lock()
+	defer ks.mutex.Unlock()
+	ks.fetched = time.Now()
+	if err != nil {
+		return err
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return err
+	}
+	ks.keys = keys
+	return nil
+}
+
+func parseJWKS(data []byte) (map[string]jwksKey, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]jwksKey, len(set.Keys))
+	var skipped error
+	for _, jwk := range set.Keys {
+		if jwk.Use != emptyString && jwk.Use != "sig" {
+			continue
+		}
+		//keys of unsupported type or curve and malformed keys are skipped,
+		//so they do not make other keys of the set unusable
+		key, alg, err := jwk.publicKey()
+		if err != nil {
+			if skipped == nil {
+				skipped = err
+			}
+			continue
+		}
+		if jwk.Alg != emptyString {
+			alg = jwk.Alg
+		}
+		keys[jwk.Kid] = jwksKey{alg: alg, key: key}
+	}
+	if len(keys) == 0 && skipped != nil {
+		return nil, skipped
+	}
+	return keys, nil
+}
+
+func (jwk *jsonWebKey) publicKey() (interface{}, string, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, emptyString, err
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, emptyString, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, RS256, nil
+	case "EC":
+		if jwk.Crv != "P-256" {
+			return nil, emptyString, fmt.Errorf("mint: unsupported curve %s in key %s", jwk.Crv, jwk.Kid)
+		}
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, emptyString, err
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, emptyString, err
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, ES256, nil
+	case "oct":
+		secret, err := base64.RawURLEncoding.DecodeString(jwk.K)
+		if err != nil {
+			return nil, emptyString, err
+		}
+		return secret, HS256, nil
+	}
+	return nil, emptyString, fmt.Errorf("mint: unsupported key type %s in key %s", jwk.Kty, jwk.Kid)
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
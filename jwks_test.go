@@ -0,0 +1,144 @@
+package mint
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func rsaJWK(kid string, key *rsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kty": "RSA",
+		"kid": kid,
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func marshalJWKS(t *testing.T, keys ...map[string]string) []byte {
+	t.Helper()
+	data, err := json.Marshal(map[string]interface{}{"keys": keys})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func writeJWKS(t *testing.T, path string, keys ...map[string]string) {
+	t.Helper()
+	if err := ioutil.WriteFile(path, marshalJWKS(t, keys...), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestJWKSKeyRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jwks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "jwks.json")
+	oldKey, newKey := generateRSAKey(t), generateRSAKey(t)
+	writeJWKS(t, path, rsaJWK("old", &oldKey.PublicKey))
+
+	keySet := NewJWKSFromFile(path)
+	keySet.MinRefreshInterval = 0
+	config := JWTConfig{KeySet: keySet, Algorithms: testAlgorithms}
+	if _, _, err := config.verify(signRS256(t, oldKey, "old", Claims{}), time.Now()); err != nil {
+		t.Fatalf("verify() with old key error = %v", err)
+	}
+
+	writeJWKS(t, path, rsaJWK("new", &newKey.PublicKey))
+	//unknown kid reloads key set
+	if _, _, err := config.verify(signRS256(t, newKey, "new", Claims{}), time.Now()); err != nil {
+		t.Fatalf("verify() with rotated key error = %v", err)
+	}
+	if _, _, err := config.verify(signRS256(t, oldKey, "old", Claims{}), time.Now()); err != ErrKeyNotFound {
+		t.Errorf("verify() with removed key error = %v, want %v", err, ErrKeyNotFound)
+	}
+}
+
+func TestJWKSMinRefreshInterval(t *testing.T) {
+	key := generateRSAKey(t)
+	var loads int32
+	keySet := newJWKS(func() ([]byte, error) {
+		atomic.AddInt32(&loads, 1)
+		return marshalJWKS(t, rsaJWK("current", &key.PublicKey)), nil
+	})
+	for iter := 0; iter < 3; iter++ {
+		if _, err := keySet.Key("unknown", RS256); err != ErrKeyNotFound {
+			t.Errorf("Key() error = %v, want %v", err, ErrKeyNotFound)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("key set loaded %d times, want 1", loads)
+	}
+}
+
+func TestJWKSConcurrentLoad(t *testing.T) {
+	key := generateRSAKey(t)
+	var loads int32
+	keySet := newJWKS(func() ([]byte, error) {
+		atomic.AddInt32(&loads, 1)
+		time.Sleep(10 * time.Millisecond)
+		return marshalJWKS(t, rsaJWK("current", &key.PublicKey)), nil
+	})
+	var wg sync.WaitGroup
+	for iter := 0; iter < 10; iter++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := keySet.Key("current", RS256); err != nil {
+				t.Errorf("Key() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if loads != 1 {
+		t.Errorf("key set loaded %d times, want 1", loads)
+	}
+}
+
+func TestJWKSSkipsUnsupportedKeys(t *testing.T) {
+	key := generateRSAKey(t)
+	data := marshalJWKS(t,
+		map[string]string{"kty": "OKP", "kid": "ed", "crv": "Ed25519", "x": "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"},
+		map[string]string{"kty": "EC", "kid": "p384", "crv": "P-384", "x": "AA", "y": "AA"},
+		map[string]string{"kty": "RSA", "kid": "enc", "use": "enc", "n": "AA", "e": "AQAB"},
+		rsaJWK("sig", &key.PublicKey),
+	)
+	keySet := newJWKS(func() ([]byte, error) { return data, nil })
+	config := JWTConfig{KeySet: keySet, Algorithms: testAlgorithms}
+	if _, _, err := config.verify(signRS256(t, key, "sig", Claims{}), time.Now()); err != nil {
+		t.Errorf("verify() error = %v", err)
+	}
+	if _, err := keySet.Key("ed", RS256); err != ErrKeyNotFound {
+		t.Errorf("Key() of unsupported key error = %v, want %v", err, ErrKeyNotFound)
+	}
+
+	unsupported := marshalJWKS(t, map[string]string{"kty": "OKP", "kid": "ed", "crv": "Ed25519", "x": "AA"})
+	if _, err := parseJWKS(unsupported); err == nil {
+		t.Error("parseJWKS() of set without usable key succeeded")
+	}
+}
+
+func TestJWKSRejectsKeyOfOtherAlgorithm(t *testing.T) {
+	key := generateRSAKey(t)
+	keySet := newJWKS(func() ([]byte, error) {
+		return marshalJWKS(t, rsaJWK("rsa", &key.PublicKey)), nil
+	})
+	config := JWTConfig{KeySet: keySet, Algorithms: testAlgorithms}
+	token := signHS256(t, key.PublicKey.N.Bytes(), "rsa", Claims{})
+	if _, _, err := config.verify(token, time.Now()); err == nil {
+		t.Error("verify() of HS256 token against RSA key succeeded")
+	}
+}
@@ -0,0 +1,344 @@
+package mint
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+)
+
+//JWT algorithms
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+	ES256 = "ES256"
+)
+
+//JWT errors
+var (
+	ErrTokenMalformed   = errors.New("mint: token is malformed")
+	ErrTokenAlgorithm   = errors.New("mint: token algorithm is not allowed")
+	ErrTokenSignature   = errors.New("mint: token signature is invalid")
+	ErrTokenExpired     = errors.New("mint: token is expired")
+	ErrTokenNotValidYet = errors.New("mint: token is not valid yet")
+	ErrTokenIssuer      = errors.New("mint: token issuer is invalid")
+	ErrTokenAudience    = errors.New("mint: token audience is invalid")
+	ErrKeyNotFound      = errors.New("mint: key not found")
+	ErrNoClaims         = errors.New("mint: request does not have claims")
+)
+
+//Claims of JWT
+type Claims map[string]interface{}
+
+//String returns string claim
+func (cl Claims) String(key string) (string, bool) {
+	value, ok := cl[key].(string)
+	return value, ok
+}
+
+//Strings returns claim which can be string or array of strings
+func (cl Claims) Strings(key string) []string {
+	switch value := cl[key].(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, v := range value {
+			if str, ok := v.(string); ok {
+				values = append(values, str)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+//Time returns numeric date claim
+func (cl Claims) Time(key string) (time.Time, bool) {
+	value, ok := cl[key].(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	seconds := int64(value)
+	return time.Unix(seconds, int64((value-float64(seconds))*float64(time.Second))), true
+}
+
+//Subject returns sub claim
+func (cl Claims) Subject() string {
+	value, _ := cl.String("sub")
+	return value
+}
+
+//Issuer returns iss claim
+func (cl Claims) Issuer() string {
+	value, _ := cl.String("iss")
+	return value
+}
+
+//Audience returns aud claim
+func (cl Claims) Audience() []string {
+	return cl.Strings("aud")
+}
+
+//ExpiresAt returns exp claim
+func (cl Claims) ExpiresAt() (time.Time, bool) {
+	return cl.Time("exp")
+}
+
+//NotBefore returns nbf claim
+func (cl Claims) NotBefore() (time.Time, bool) {
+	return cl.Time("nbf")
+}
+
+//Scopes returns scopes from scope or scp claim
+func (cl Claims) Scopes() []string {
+	if scope, ok := cl.String("scope"); ok {
+		return strings.Fields(scope)
+	}
+	return cl.Strings("scp")
+}
+
+//KeySet provides key to verify token signature
+type KeySet interface {
+	Key(kid string, alg string) (interface{}, error)
+}
+
+//KeySetFunc is function implementing KeySet
+type KeySetFunc func(kid string, alg string) (interface{}, error)
+
+//Key #
+func (fn KeySetFunc) Key(kid string, alg string) (interface{}, error) {
+	return fn(kid, alg)
+}
+
+//StaticKey creates KeySet with single key
+//key must be []byte for HS256, *rsa.PublicKey for RS256 and *ecdsa.PublicKey for ES256
+func StaticKey(key interface{}) KeySet {
+	return KeySetFunc(func(kid string, alg string) (interface{}, error) {
+		return key, nil
+	})
+}
+
+//JWTConfig configures JWT middleware
+type JWTConfig struct {
+	//KeySet provides keys to verify signature
+	KeySet KeySet
+	//Algorithms allowed, default is HS256, RS256 and ES256
+	Algorithms []string
+	//Issuer expected in iss claim
+	Issuer string
+	//Audience one of which is expected in aud claim
+	Audience []string
+	//ClockSkew tolerated while checking exp and nbf
+	ClockSkew time.Duration
+	//TokenFunc extracts token from request, default is bearer token from Authorization header
+	TokenFunc func(*Context) (string, bool)
+	//Optional lets requests without token pass
+	Optional bool
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+//JWT creates JWT verification middleware
+//Claims are available using c.Claims() and principal name is sub claim
+func JWT(config JWTConfig) HandlerFunc {
+	if len(config.Algorithms) == 0 {
+		config.Algorithms = []string{HS256, RS256, ES256}
+	}
+	if config.TokenFunc == nil {
+		config.TokenFunc = bearerToken
+	}
+	return func(c *Context) {
+		token, ok := config.TokenFunc(c)
+		if !ok {
+			if config.Optional {
+				c.Next()
+				return
+			}
+			unauthorized(c, SchemeBearer)
+			return
+		}
+		claims, payload, err := config.verify(token, time.Now())
+		if err != nil {
+			c.Error(err)
+			unauthorized(c, SchemeBearer+` error="invalid_token"`)
+			return
+		}
+		c.claims = claims
+		c.rawClaims = payload
+		c.SetPrincipal(&Principal{Name: claims.Subject(), Scheme: SchemeBearer, Data: claims})
+		c.Next()
+	}
+}
+
+func (config *JWTConfig) verify(token string, now time.Time) (Claims, []byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, nil, ErrTokenMalformed
+	}
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, nil, err
+	}
+	if !containsString(config.Algorithms, header.Alg) {
+		return nil, nil, ErrTokenAlgorithm
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, nil, ErrTokenMalformed
+	}
+	key, err := config.KeySet.Key(header.Kid, header.Alg)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = verifySignature(header.Alg, key, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, nil, err
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, nil, ErrTokenMalformed
+	}
+	claims := make(Claims)
+	if err = json.Unmarshal(payload, &claims); err != nil {
+		return nil, nil, ErrTokenMalformed
+	}
+	if err = config.validate(claims, now); err != nil {
+		return nil, nil, err
+	}
+	return claims, payload, nil
+}
+
+func (config *JWTConfig) validate(claims Claims, now time.Time) error {
+	if exp, ok := claims.ExpiresAt(); ok && !now.Before(exp.Add(config.ClockSkew)) {
+		return ErrTokenExpired
+	}
+	if nbf, ok := claims.NotBefore(); ok && now.Add(config.ClockSkew).Before(nbf) {
+		return ErrTokenNotValidYet
+	}
+	if config.Issuer != emptyString && claims.Issuer() != config.Issuer {
+		return ErrTokenIssuer
+	}
+	if len(config.Audience) > 0 {
+		for _, aud := range claims.Audience() {
+			if containsString(config.Audience, aud) {
+				return nil
+			}
+		}
+		return ErrTokenAudience
+	}
+	return nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return ErrTokenMalformed
+	}
+	if err = json.Unmarshal(data, v); err != nil {
+		return ErrTokenMalformed
+	}
+	return nil
+}
+
+func verifySignature(alg string, key interface{}, signingInput string, signature []byte) error {
+	digest := sha256.Sum256([]byte(signingInput))
+	switch alg {
+	case HS256:
+		secret, ok := key.([]byte)
+		if !ok {
+			return ErrTokenAlgorithm
+		}
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(signingInput))
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return ErrTokenSignature
+		}
+	case RS256:
+		publicKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return ErrTokenAlgorithm
+		}
+		if rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], signature) != nil {
+			return ErrTokenSignature
+		}
+	case ES256:
+		publicKey, ok := key.(*ecdsa.PublicKey)
+		if !ok || publicKey.Curve != elliptic.P256() {
+			return ErrTokenAlgorithm
+		}
+		if len(signature) != 64 {
+			return ErrTokenSignature
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(publicKey, digest[:], r, s) {
+			return ErrTokenSignature
+		}
+	default:
+		return ErrTokenAlgorithm
+	}
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+//Claims returns claims of verified JWT, nil if request does not have one
+func (c *Context) Claims() Claims {
+	return c.claims
+}
+
+//BindClaims decodes claims of verified JWT into v
+func (c *Context) BindClaims(v interface{}) error {
+	if c.rawClaims == nil {
+		return ErrNoClaims
+	}
+	return json.NewDecoder(bytes.NewReader(c.rawClaims)).Decode(v)
+}
+
+//RequireClaim creates middleware allowing requests whose claim has one of values
+//It should be used after JWT middleware, for example at group level
+func RequireClaim(key string, values ...string) HandlerFunc {
+	return func(c *Context) {
+		for _, value := range c.claims.Strings(key) {
+			if len(values) == 0 || containsString(values, value) {
+				c.Next()
+				return
+			}
+		}
+		ErrorMessage(c, http.StatusForbidden, "Forbidden")
+	}
+}
+
+//RequireScopes creates middleware allowing requests whose token has all scopes
+func RequireScopes(scopes ...string) HandlerFunc {
+	return func(c *Context) {
+		granted := c.claims.Scopes()
+		for _, scope := range scopes {
+			if !containsString(granted, scope) {
+				ErrorMessage(c, http.StatusForbidden, "Insufficient scope")
+				return
+			}
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,146 @@
+package mint
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+	"time"
+)
+
+//testAlgorithms are default algorithms of JWT middleware
+var testAlgorithms = []string{HS256, RS256, ES256}
+
+func encodeSegment(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func signHS256(t *testing.T, secret []byte, kid string, claims Claims) string {
+	t.Helper()
+	input := encodeSegment(t, jwtHeader{Alg: HS256, Kid: kid}) + "." + encodeSegment(t, claims)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(input))
+	return input + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims Claims) string {
+	t.Helper()
+	input := encodeSegment(t, jwtHeader{Alg: RS256, Kid: kid}) + "." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(input))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func generateRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestJWTRejectsHS256TokenForRSAKey(t *testing.T) {
+	key := generateRSAKey(t)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := JWTConfig{KeySet: StaticKey(&key.PublicKey), Algorithms: testAlgorithms}
+	//public key is known to attacker, so it must not be usable as HMAC secret
+	for _, secret := range [][]byte{der, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})} {
+		token := signHS256(t, secret, "", Claims{"sub": "attacker"})
+		if _, _, err := config.verify(token, time.Now()); err != ErrTokenAlgorithm {
+			t.Errorf("verify() error = %v, want %v", err, ErrTokenAlgorithm)
+		}
+	}
+	token := signRS256(t, key, "", Claims{"sub": "user"})
+	claims, _, err := config.verify(token, time.Now())
+	if err != nil {
+		t.Fatalf("verify() error = %v", err)
+	}
+	if claims.Subject() != "user" {
+		t.Errorf("Subject() = %q, want %q", claims.Subject(), "user")
+	}
+}
+
+func TestJWTRejectsAlgorithmNotAllowed(t *testing.T) {
+	secret := []byte("secret")
+	config := JWTConfig{KeySet: StaticKey(secret), Algorithms: []string{RS256}}
+	token := signHS256(t, secret, "", Claims{"sub": "user"})
+	if _, _, err := config.verify(token, time.Now()); err != ErrTokenAlgorithm {
+		t.Errorf("verify() error = %v, want %v", err, ErrTokenAlgorithm)
+	}
+}
+
+func TestJWTClockSkew(t *testing.T) {
+	secret := []byte("secret")
+	now := time.Unix(1500000000, 0)
+	tests := []struct {
+		name   string
+		claims Claims
+		skew   time.Duration
+		want   error
+	}{
+		{"valid", Claims{"exp": float64(now.Unix() + 10), "nbf": float64(now.Unix() - 10)}, 0, nil},
+		{"expired", Claims{"exp": float64(now.Unix() - 10)}, 0, ErrTokenExpired},
+		{"expires now", Claims{"exp": float64(now.Unix())}, 0, ErrTokenExpired},
+		{"expired within skew", Claims{"exp": float64(now.Unix() - 10)}, time.Minute, nil},
+		{"expired beyond skew", Claims{"exp": float64(now.Unix() - 120)}, time.Minute, ErrTokenExpired},
+		{"not valid yet", Claims{"nbf": float64(now.Unix() + 10)}, 0, ErrTokenNotValidYet},
+		{"not valid yet within skew", Claims{"nbf": float64(now.Unix() + 10)}, time.Minute, nil},
+		{"not valid yet beyond skew", Claims{"nbf": float64(now.Unix() + 120)}, time.Minute, ErrTokenNotValidYet},
+	}
+	for _, test := range tests {
+		config := JWTConfig{KeySet: StaticKey(secret), Algorithms: testAlgorithms, ClockSkew: test.skew}
+		token := signHS256(t, secret, "", test.claims)
+		if _, _, err := config.verify(token, now); err != test.want {
+			t.Errorf("%s: verify() error = %v, want %v", test.name, err, test.want)
+		}
+	}
+}
+
+func TestJWTIssuerAndAudience(t *testing.T) {
+	secret := []byte("secret")
+	config := JWTConfig{KeySet: StaticKey(secret), Algorithms: testAlgorithms, Issuer: "mint", Audience: []string{"api"}}
+	tests := []struct {
+		claims Claims
+		want   error
+	}{
+		{Claims{"iss": "mint", "aud": "api"}, nil},
+		{Claims{"iss": "mint", "aud": []interface{}{"web", "api"}}, nil},
+		{Claims{"iss": "other", "aud": "api"}, ErrTokenIssuer},
+		{Claims{"iss": "mint", "aud": "web"}, ErrTokenAudience},
+	}
+	for _, test := range tests {
+		token := signHS256(t, secret, "", test.claims)
+		if _, _, err := config.verify(token, time.Now()); err != test.want {
+			t.Errorf("verify(%v) error = %v, want %v", test.claims, err, test.want)
+		}
+	}
+}
+
+func TestJWTSignature(t *testing.T) {
+	config := JWTConfig{KeySet: StaticKey([]byte("secret")), Algorithms: testAlgorithms}
+	token := signHS256(t, []byte("other"), "", Claims{"sub": "user"})
+	if _, _, err := config.verify(token, time.Now()); err != ErrTokenSignature {
+		t.Errorf("verify() error = %v, want %v", err, ErrTokenSignature)
+	}
+	if _, _, err := config.verify("a.b", time.Now()); err != ErrTokenMalformed {
+		t.Errorf("verify() error = %v, want %v", err, ErrTokenMalformed)
+	}
+}
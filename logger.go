@@ -3,7 +3,9 @@ package mint
 // inspired from gin logger (both are same , but added some code)
 import (
 	"fmt"
+	"io"
 	"net/http"
+	"os"
 	"time"
 )
 
@@ -81,19 +83,29 @@ func (l *Logger) getResetColor() string {
 
 //Print prints log
 func (l *Logger) Print() {
+	l.Fprint(os.Stdout)
+}
+
+//Fprint writes log to w
+func (l *Logger) Fprint(w io.Writer) {
 	statusColor := l.getStatusCodeColor()
 	methodColor := l.getMethodColor()
 	resetColor := l.getResetColor()
-	fmt.Println(fmt.Sprintf("[Mint] %v |%s %3d %s| %13v | %15s |%s %-7s %s| %s > %v Bytes",
+	userName := l.UserName
+	if userName == emptyString {
+		userName = "-"
+	}
+	fmt.Fprintln(w, fmt.Sprintf("[Mint] %v |%s %3d %s| %13v | %15s | %s |%s %-7s %s| %s > %v Bytes",
 		l.TimeStamp.Format("2006/01/02 - 15:04:05"),
 		statusColor, l.StatusCode, resetColor,
 		l.Latency,
 		l.ClientIP,
+		userName,
 		methodColor, l.Method, resetColor,
 		l.Path,
 		l.BodySize,
 	))
 	for _, err := range l.Errors {
-		fmt.Println(fmt.Errorf("%s", err.Error()))
+		fmt.Fprintln(w, fmt.Errorf("%s", err.Error()))
 	}
 }
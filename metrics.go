@@ -0,0 +1,298 @@
+package mint
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+//DefaultBuckets are default upper bounds of latency histogram in seconds
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+//PoolStats is number of objects allocated by pools of application,
+//allocations growing with requests mean pooled objects are not reused
+type PoolStats struct {
+	Contexts    uint64
+	GzipWriters uint64
+	Buffers     uint64
+}
+
+//PoolStats returns allocation statistics of pools of application
+func (mt *Mint) PoolStats() PoolStats {
+	return PoolStats{
+		Contexts:    atomic.LoadUint64(&mt.pools.Contexts),
+		GzipWriters: atomic.LoadUint64(&mt.pools.GzipWriters),
+		Buffers:     mt.bufferPool.Allocated(),
+	}
+}
+
+//MetricsConfig configures metrics
+type MetricsConfig struct {
+	//Path of metrics endpoint, default is /metrics
+	Path string
+	//Namespace is prefix of metric names, default is mint
+	Namespace string
+	//Buckets are upper bounds of latency histogram in seconds, default is DefaultBuckets
+	Buckets []float64
+}
+
+//Metrics records request counts, latencies and in-flight requests labeled
+//by method and route template, and exposes them in Prometheus text format
+type Metrics struct {
+	config MetricsConfig
+	//requests are *uint64 counters by requestLabels, durations are *histogram
+	//and inFlight are *int64 gauges by routeLabels, series are updated atomically
+	requests  sync.Map
+	durations sync.Map
+	inFlight  sync.Map
+}
+
+type routeLabels struct {
+	method string
+	route  string
+}
+
+type requestLabels struct {
+	routeLabels
+	code string
+}
+
+type histogram struct {
+	count uint64
+	//sum is bits of float64 sum
+	sum uint64
+	//counts are not cumulative, count of le bucket is sum of counts up to it
+	counts []uint64
+}
+
+//NewMetrics creates metrics
+func NewMetrics(config MetricsConfig) *Metrics {
+	if config.Path == emptyString {
+		config.Path = "/metrics"
+	}
+	if config.Namespace == emptyString {
+		config.Namespace = "mint"
+	}
+	if len(config.Buckets) == 0 {
+		config.Buckets = DefaultBuckets
+	}
+	config.Buckets = append([]float64(nil), config.Buckets...)
+	sort.Float64s(config.Buckets)
+	return &Metrics{config: config}
+}
+
+//Metrics enables metrics of all requests and serves them at config.Path
+func (mt *Mint) Metrics(config MetricsConfig) *Metrics {
+	metrics := NewMetrics(config)
+	mt.Use(metrics.Middleware())
+	mt.GET(metrics.config.Path, metrics.Handler()).Undocumented()
+	return metrics
+}
+
+//Middleware creates middleware recording metrics of requests
+func (m *Metrics) Middleware() HandlerFunc {
+	return func(c *Context) {
+		start := time.Now()
+		method := c.Req.Method
+		if !containsMethod(anyMethods, method) {
+			//unknown methods share label to keep cardinality bounded
+			method = "OTHER"
+		}
+		labels := routeLabels{method: method, route: c.HandlerContext.Template()}
+		inFlight := m.gauge(labels)
+		atomic.AddInt64(inFlight, 1)
+		//request is recorded even if handler panics, so in flight gauge does not leak
+		finished := false
+		defer func() {
+			atomic.AddInt64(inFlight, -1)
+			status := strconv.Itoa(c.responseStatus(finished))
+			atomic.AddUint64(m.counter(requestLabels{routeLabels: labels, code: status}), 1)
+			m.histogram(labels).observe(m.config.Buckets, time.Since(start).Seconds())
+		}()
+		c.Next()
+		finished = true
+	}
+}
+
+func (m *Metrics) counter(labels requestLabels) *uint64 {
+	if value, ok := m.requests.Load(labels); ok {
+		return value.(*uint64)
+	}
+	value, _ := m.requests.LoadOrStore(labels, new(uint64))
+	return value.(*uint64)
+}
+
+func (m *Metrics) gauge(labels routeLabels) *int64 {
+	if value, ok := m.inFlight.Load(labels); ok {
+		return value.(*int64)
+	}
+	value, _ := m.inFlight.LoadOrStore(labels, new(int64))
+	return value.(*int64)
+}
+
+func (m *Metrics) histogram(labels routeLabels) *histogram {
+	if value, ok := m.durations.Load(labels); ok {
+		return value.(*histogram)
+	}
+	value, _ := m.durations.LoadOrStore(labels, &histogram{counts: make([]uint64, len(m.config.Buckets))})
+	return value.(*histogram)
+}
+
+//observe records value, count is incremented before bucket, so buckets
+//read before count never exceed it
+func (hist *histogram) observe(buckets []float64, value float64) {
+	atomic.AddUint64(&hist.count, 1)
+	for index, bound := range buckets {
+		if value <= bound {
+			atomic.AddUint64(&hist.counts[index], 1)
+			break
+		}
+	}
+	for {
+		old := atomic.LoadUint64(&hist.sum)
+		sum := math.Float64bits(math.Float64frombits(old) + value)
+		if atomic.CompareAndSwapUint64(&hist.sum, old, sum) {
+			return
+		}
+	}
+}
+
+//Handler creates handler serving metrics in Prometheus text format
+func (m *Metrics) Handler() HandlerFunc {
+	return func(c *Context) {
+		buffer := c.HandlerContext.Mint.bufferPool.Get()
+		m.Write(buffer, c.HandlerContext.Mint)
+		c.SetHeader(contentType, []string{metricsContentType})
+		c.Status(http.StatusOK)
+		size, err := c.Res.Write(buffer.Bytes())
+		c.HandlerContext.Mint.bufferPool.Put(buffer)
+		c.setSize(size)
+		c.Error(err)
+	}
+}
+
+//Write writes metrics of requests, pools of mt and Go runtime in Prometheus text format
+func (m *Metrics) Write(w io.Writer, mt *Mint) {
+	ns := m.config.Namespace
+	var requests []requestLabels
+	m.requests.Range(func(key, value interface{}) bool {
+		requests = append(requests, key.(requestLabels))
+		return true
+	})
+	sort.Slice(requests, func(i, j int) bool {
+		if requests[i].routeLabels != requests[j].routeLabels {
+			return requests[i].routeLabels.less(requests[j].routeLabels)
+		}
+		return requests[i].code < requests[j].code
+	})
+	writeHeader(w, ns+"_http_requests_total", "counter", "Total number of HTTP requests.")
+	for _, labels := range requests {
+		fmt.Fprintf(w, "%s_http_requests_total{method=%s,route=%s,code=%s} %d\n",
+			ns, quoteLabel(labels.method), quoteLabel(labels.route), quoteLabel(labels.code), atomic.LoadUint64(m.counter(labels)))
+	}
+	var routes []routeLabels
+	m.durations.Range(func(key, value interface{}) bool {
+		routes = append(routes, key.(routeLabels))
+		return true
+	})
+	sort.Slice(routes, func(i, j int) bool { return routes[i].less(routes[j]) })
+	name := ns + "_http_request_duration_seconds"
+	writeHeader(w, name, "histogram", "Latency of HTTP requests in seconds.")
+	for _, labels := range routes {
+		hist := m.histogram(labels)
+		prefix := "method=" + quoteLabel(labels.method) + ",route=" + quoteLabel(labels.route)
+		cumulative := uint64(0)
+		for index, bound := range m.config.Buckets {
+			cumulative += atomic.LoadUint64(&hist.counts[index])
+			fmt.Fprintf(w, "%s_bucket{%s,le=%s} %d\n", name, prefix, quoteLabel(formatFloat(bound)), cumulative)
+		}
+		count := atomic.LoadUint64(&hist.count)
+		fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, prefix, count)
+		fmt.Fprintf(w, "%s_sum{%s} %s\n", name, prefix, formatFloat(math.Float64frombits(atomic.LoadUint64(&hist.sum))))
+		fmt.Fprintf(w, "%s_count{%s} %d\n", name, prefix, count)
+	}
+	routes = routes[:0]
+	m.inFlight.Range(func(key, value interface{}) bool {
+		routes = append(routes, key.(routeLabels))
+		return true
+	})
+	sort.Slice(routes, func(i, j int) bool { return routes[i].less(routes[j]) })
+	writeHeader(w, ns+"_http_requests_in_flight", "gauge", "Number of HTTP requests being served.")
+	for _, labels := range routes {
+		fmt.Fprintf(w, "%s_http_requests_in_flight{method=%s,route=%s} %d\n",
+			ns, quoteLabel(labels.method), quoteLabel(labels.route), atomic.LoadInt64(m.gauge(labels)))
+	}
+
+	if mt != nil {
+		pools := mt.PoolStats()
+		writeMetric(w, ns+"_pool_contexts_allocated_total", "counter", "Number of contexts allocated by context pool.", float64(pools.Contexts))
+		writeMetric(w, ns+"_pool_gzip_writers_allocated_total", "counter", "Number of gzip writers allocated by gzip writer pool.", float64(pools.GzipWriters))
+		writeMetric(w, ns+"_pool_buffers_allocated_total", "counter", "Number of buffers allocated by buffer pool.", float64(pools.Buffers))
+	}
+	writeRuntimeMetrics(w)
+}
+
+func (rl routeLabels) less(other routeLabels) bool {
+	if rl.route != other.route {
+		return rl.route < other.route
+	}
+	return rl.method < other.method
+}
+
+func writeRuntimeMetrics(w io.Writer) {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	writeMetric(w, "go_goroutines", "gauge", "Number of goroutines that currently exist.", float64(runtime.NumGoroutine()))
+	writeMetric(w, "go_gc_cycles_total", "counter", "Number of completed GC cycles.", float64(stats.NumGC))
+	writeMetric(w, "go_gc_pause_seconds_total", "counter", "Total GC pause time in seconds.", float64(stats.PauseTotalNs)/1e9)
+	writeMetric(w, "go_memstats_alloc_bytes", "gauge", "Number of bytes allocated and still in use.", float64(stats.Alloc))
+	writeMetric(w, "go_memstats_alloc_bytes_total", "counter", "Total number of bytes allocated, even if freed.", float64(stats.TotalAlloc))
+	writeMetric(w, "go_memstats_sys_bytes", "gauge", "Number of bytes obtained from system.", float64(stats.Sys))
+	writeMetric(w, "go_memstats_heap_inuse_bytes", "gauge", "Number of heap bytes that are in use.", float64(stats.HeapInuse))
+	writeMetric(w, "go_memstats_heap_objects", "gauge", "Number of allocated objects.", float64(stats.HeapObjects))
+	writeMetric(w, "go_memstats_mallocs_total", "counter", "Total number of mallocs.", float64(stats.Mallocs))
+	writeMetric(w, "go_memstats_frees_total", "counter", "Total number of frees.", float64(stats.Frees))
+}
+
+func writeHeader(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func writeMetric(w io.Writer, name, kind, help string, value float64) {
+	writeHeader(w, name, kind, help)
+	fmt.Fprintf(w, "%s %s\n", name, formatFloat(value))
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+//quoteLabel quotes label value escaping backslash, quote and new line
+func quoteLabel(value string) string {
+	var quoted bytes.Buffer
+	quoted.WriteByte('"')
+	for _, char := range value {
+		switch char {
+		case '\\':
+			quoted.WriteString(`\\`)
+		case '"':
+			quoted.WriteString(`\"`)
+		case '\n':
+			quoted.WriteString(`\n`)
+		default:
+			quoted.WriteRune(char)
+		}
+	}
+	quoted.WriteByte('"')
+	return quoted.String()
+}
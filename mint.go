@@ -2,16 +2,26 @@ package mint
 
 import (
 	"compress/gzip"
+	"context"
 	"fmt"
+	"io"
 	"net/http"
+	"os"
+	"os/signal"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
 
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
 )
 
+//ShutdownTimeout is time Run waits for active requests to finish on interrupt
+var ShutdownTimeout = 10 * time.Second
+
 var (
-	mutex sync.RWMutex
 	//DefaultHandlerWithLogger middlewares including logger
 	DefaultHandlerWithLogger = []HandlerFunc{loggerMW}
 )
@@ -27,21 +37,36 @@ type Mint struct {
 	//handlers contains HandlersContext information
 	handlers         []*HandlerContext
 	groupHandlers    []*HandlersGroup
-	store            map[string]interface{}
+	routes           []*HandlerContext
+	methodRoutes     []methodRoute
+	container        *container
+	server           *http.Server
+	serverMutex      sync.Mutex
+	logOutput        io.Writer
+	afterRequest     []func(c *Context)
+	health           *Health
+	shuttingDown     int32
 	staticPath       string
 	staticHandler    http.Handler
 	router           *mux.Router
 	contextPool      *sync.Pool
 	gzipWriterPool   *sync.Pool
 	bufferPool       *BufferPool
+	pools            *PoolStats
 	built            bool
 	strictSlash      bool
+	cors             bool
+	debug            bool
+	openAPIInfo      OpenAPIInfo
+	versions         []*Versions
+	converters       map[string]*converter
 	notFoundHandler  *HandlerContext
 	methodNotAllowed *HandlerContext
 }
 
 //Path sets URL Path to handler
 func (mt *Mint) Path(path string) *HandlerContext {
+	mt.checkNotBuilt()
 	handlerContext := new(HandlerContext)
 	mt.handlers = append(mt.handlers, handlerContext)
 	return handlerContext.Path(path)
@@ -49,27 +74,57 @@ func (mt *Mint) Path(path string) *HandlerContext {
 
 //StrictSlash enable strictslash in router
 func (mt *Mint) StrictSlash(strictSlash bool) *Mint {
+	mt.checkNotBuilt()
 	mt.strictSlash = strictSlash
 	return mt
 }
 
+//Debug enables debug mode, route table is printed at startup in debug mode
+func (mt *Mint) Debug(debug bool) *Mint {
+	mt.debug = debug
+	return mt
+}
+
+//LogOutput sets writer of request logs, default is os.Stdout
+func (mt *Mint) LogOutput(w io.Writer) *Mint {
+	mt.checkNotBuilt()
+	mt.logOutput = w
+	return mt
+}
+
+func (mt *Mint) logWriter() io.Writer {
+	if mt.logOutput == nil {
+		return os.Stdout
+	}
+	return mt.logOutput
+}
+
+//AfterRequest registers hook which runs after handlers of each request
+//finish, before context is reset
+func (mt *Mint) AfterRequest(hook func(c *Context)) *Mint {
+	mt.checkNotBuilt()
+	mt.afterRequest = append(mt.afterRequest, hook)
+	return mt
+}
+
 //Get the value from store by key
 func (mt *Mint) Get(key string) (interface{}, bool) {
-	mutex.RLock()
-	value, ok := mt.store[key]
-	mutex.RUnlock()
+	mt.container.mutex.RLock()
+	value, ok := mt.container.values[key]
+	mt.container.mutex.RUnlock()
 	return value, ok
 }
 
 //Set the value to store with key
 func (mt *Mint) Set(key string, value interface{}) {
-	mutex.Lock()
-	mt.store[key] = value
-	mutex.Unlock()
+	mt.container.mutex.Lock()
+	mt.container.values[key] = value
+	mt.container.mutex.Unlock()
 }
 
 //Handler registers single handlers context
 func (mt *Mint) Handler(hc *HandlerContext) *Mint {
+	mt.checkNotBuilt()
 	mt.handlers = append(mt.handlers, hc)
 	return mt
 }
@@ -84,18 +139,21 @@ func (mt *Mint) Handlers(hsc []*HandlerContext) *Mint {
 
 //NotFoundHandler registers not found handler context
 func (mt *Mint) NotFoundHandler(hc *HandlerContext) {
+	mt.checkNotBuilt()
 	hc.Mint = mt
 	mt.notFoundHandler = hc
 }
 
 //MethodNotAllowedHandler registers method not allowed handler
 func (mt *Mint) MethodNotAllowedHandler(hc *HandlerContext) {
+	mt.checkNotBuilt()
 	hc.Mint = mt
 	mt.methodNotAllowed = hc
 }
 
 //HandleStatic registers a new handler to handle static content such as img, css, html, js.
 func (mt *Mint) HandleStatic(path string, dir string) {
+	mt.checkNotBuilt()
 	mt.staticPath = path
 	mt.staticHandler = http.FileServer(http.Dir(dir))
 }
@@ -119,43 +177,147 @@ func (mt *Mint) buildViews() {
 	mt.buildOtherHandlers()
 	for _, handler := range mt.handlers {
 		handler.Mint = mt
-		handler.middleware = append(mt.defaultHandler, handler.middleware...)
+		handler.middleware = chain(mt.defaultHandler, handler.middleware)
 		handler.build(mt.router)
 	}
+	for _, versions := range mt.versions {
+		versions.addFallbacks()
+	}
 	for _, handlerGroup := range mt.groupHandlers {
 		handlerGroup.mint = mt
-		handlerGroup.middleware = append(mt.defaultHandler, handlerGroup.middleware...)
+		handlerGroup.middleware = chain(mt.defaultHandler, handlerGroup.middleware)
 		handlerGroup.build(mt.router)
 	}
+	//preflight route of application is added after routes, so preflight
+	//routes of groups answer requests to their paths first
+	if mt.cors {
+		buildPreflight(mt, nil, mt.defaultHandler, mt.router)
+	}
 	if len(mt.staticPath) != 0 {
 		mt.router.PathPrefix(mt.staticPath).Handler(mt.staticHandler)
 	}
 }
 
 func (mt *Mint) buildOtherHandlers() {
-	handlers := append(mt.defaultHandler, mt.notFoundHandler.middleware...)
-	handlers = append(handlers, mt.notFoundHandler.handlers...)
-	mt.notFoundHandler.count = len(handlers)
-	mt.notFoundHandler.handlers = handlers
-	mt.router.NotFoundHandler = mt.notFoundHandler
+	if mt.notFoundHandler != nil {
+		handlers := chain(mt.defaultHandler, mt.notFoundHandler.middleware, mt.notFoundHandler.handlers)
+		mt.notFoundHandler.count = len(handlers)
+		mt.notFoundHandler.handlers = handlers
+		mt.notFoundHandler.template = NotFoundRoute
+		mt.router.NotFoundHandler = mt.notFoundHandler
+	}
+	if mt.methodNotAllowed != nil {
+		//allowMW sets Allow header and answers OPTIONS requests before method not allowed handler
+		handlers := chain(mt.defaultHandler, []HandlerFunc{allowMW}, mt.methodNotAllowed.middleware, mt.methodNotAllowed.handlers)
+		mt.methodNotAllowed.count = len(handlers)
+		mt.methodNotAllowed.handlers = handlers
+		mt.methodNotAllowed.template = MethodNotAllowedRoute
+		mt.router.MethodNotAllowedHandler = mt.methodNotAllowed
+	}
+}
 
-	handlers = append(mt.defaultHandler, mt.methodNotAllowed.middleware...)
-	handlers = append(handlers, mt.methodNotAllowed.handlers...)
-	mt.methodNotAllowed.count = len(handlers)
-	mt.methodNotAllowed.handlers = handlers
-	mt.router.MethodNotAllowedHandler = mt.methodNotAllowed
+//methodRoute is route limited to methods, routes are probed to find
+//methods allowed for a path when request method is not allowed
+type methodRoute struct {
+	route   *mux.Route
+	methods []string
+}
+
+func (mt *Mint) buildMethodRoutes() {
+	for _, hc := range mt.routes {
+		if hc.route == nil {
+			continue
+		}
+		if methods := hc.effectiveMethods(); len(methods) > 0 {
+			mt.methodRoutes = append(mt.methodRoutes, methodRoute{route: hc.route, methods: methods})
+		}
+	}
+}
+
+//effectiveMethods returns methods matched by route of handler, methods of handler
+//are limited by methods of its groups. It is nil when route matches any method
+func (hc *HandlerContext) effectiveMethods() []string {
+	var methods []string
+	for _, method := range hc.methods {
+		methods = appendMethod(methods, method)
+	}
+	if hc.autoHead && len(methods) > 0 {
+		methods = appendMethod(methods, http.MethodHead)
+	}
+	for group := hc.group; group != nil; group = group.parent {
+		if len(group.methods) == 0 {
+			continue
+		}
+		groupMethods := group.filterMethods()
+		if len(methods) == 0 {
+			for _, method := range groupMethods {
+				methods = appendMethod(methods, method)
+			}
+			continue
+		}
+		var allowed []string
+		for _, method := range methods {
+			if containsMethod(groupMethods, method) {
+				allowed = append(allowed, method)
+			}
+		}
+		if len(allowed) == 0 {
+			//methods of handler and group do not overlap, route never matches
+			return nil
+		}
+		methods = allowed
+	}
+	return methods
+}
+
+func appendMethod(methods []string, method string) []string {
+	method = strings.ToUpper(method)
+	if containsString(methods, method) {
+		return methods
+	}
+	return append(methods, method)
+}
+
+//allowedMethods returns methods of routes matching the request when method is ignored,
+//each route is matched once as mux reports routes which do not match only by method
+func (mt *Mint) allowedMethods(req *http.Request) []string {
+	var allowed []string
+	for _, mr := range mt.methodRoutes {
+		var match mux.RouteMatch
+		if mr.route.Match(req, &match) || match.MatchErr == mux.ErrMethodMismatch {
+			for _, method := range mr.methods {
+				allowed = appendMethod(allowed, method)
+			}
+		}
+	}
+	if len(allowed) > 0 && !containsString(allowed, http.MethodOptions) {
+		allowed = append(allowed, http.MethodOptions)
+	}
+	return allowed
 }
 
 //Group creates new group handlers W
 func (mt *Mint) Group(pathPrefix string) *HandlersGroup {
+	mt.checkNotBuilt()
 	handlersGroup := &HandlersGroup{}
 	handlersGroup.basePath = pathPrefix
 	mt.groupHandlers = append(mt.groupHandlers, handlersGroup)
 	return handlersGroup
 }
 
+//Host creates new group of handlers matching host template such as {tenant}.example.com,
+//host variables are available using c.Param along with path variables
+func (mt *Mint) Host(host string) *HandlersGroup {
+	mt.checkNotBuilt()
+	handlersGroup := new(HandlersGroup)
+	handlersGroup.host = host
+	mt.groupHandlers = append(mt.groupHandlers, handlersGroup)
+	return handlersGroup
+}
+
 //AddGroup adds a group to router
 func (mt *Mint) AddGroup(hg *HandlersGroup) *Mint {
+	mt.checkNotBuilt()
 	mt.groupHandlers = append(mt.groupHandlers, hg)
 	return mt
 }
@@ -179,32 +341,85 @@ func New() *Mint {
 
 //Use register new middleware
 func (mt *Mint) Use(handler ...HandlerFunc) {
+	mt.checkNotBuilt()
 	mt.defaultHandler = append(mt.defaultHandler, handler...)
 }
 
-//Build the application
+//Build the application, it cannot be configured after it is built
 func (mt *Mint) Build() *mux.Router {
 	if !mt.built {
 		mt.buildViews()
+		mt.buildMethodRoutes()
 		mt.built = true
 	}
 	return mt.router
 }
 
+//checkNotBuilt panics when application is configured after it is built,
+//as routes, handlers and middleware are not changed after build
+func (mt *Mint) checkNotBuilt() {
+	if mt.built {
+		panic("mint: cannot configure application after it is built")
+	}
+}
+
+//snapshot returns application built from copies of registered handlers and groups,
+//so routes can be inspected before application is built without freezing it
+func (mt *Mint) snapshot() *Mint {
+	if mt.built {
+		return mt
+	}
+	copied := &Mint{
+		defaultHandler:   mt.defaultHandler,
+		container:        mt.container,
+		staticPath:       mt.staticPath,
+		staticHandler:    mt.staticHandler,
+		router:           NewRouter(),
+		strictSlash:      mt.strictSlash,
+		cors:             mt.cors,
+		openAPIInfo:      mt.openAPIInfo,
+		converters:       mt.converters,
+		notFoundHandler:  mt.notFoundHandler.clone(),
+		methodNotAllowed: mt.methodNotAllowed.clone(),
+	}
+	for _, hc := range []*HandlerContext{copied.notFoundHandler, copied.methodNotAllowed} {
+		if hc != nil {
+			hc.Mint = copied
+		}
+	}
+	copied.handlers = cloneHandlers(mt.handlers)
+	groups := make(map[*HandlersGroup]*HandlersGroup)
+	for _, group := range mt.groupHandlers {
+		copied.groupHandlers = append(copied.groupHandlers, group.clone(groups))
+	}
+	for _, vs := range mt.versions {
+		versions := &Versions{mint: copied, config: vs.config}
+		for _, version := range vs.versions {
+			versions.versions = append(versions.versions, &apiVersion{name: version.name, group: groups[version.group]})
+		}
+		copied.versions = append(copied.versions, versions)
+	}
+	copied.Build()
+	return copied
+}
+
 //Simple creates new application without any defualt handlers
 func Simple() *Mint {
 	mintEngine := &Mint{}
+	mintEngine.pools = new(PoolStats)
 	mintEngine.contextPool = &sync.Pool{
 		New: newContextPool(mintEngine),
 	}
 	mintEngine.gzipWriterPool = &sync.Pool{
 		New: func() interface{} {
+			atomic.AddUint64(&mintEngine.pools.GzipWriters, 1)
 			return gzip.NewWriter(nil)
 		},
 	}
 	mintEngine.bufferPool = NewBufferPool()
-	mintEngine.store = make(map[string]interface{})
+	mintEngine.container = newContainer()
 	mintEngine.router = NewRouter()
+	mintEngine.registerDefaultConverters()
 	mintEngine.built = false
 	return mintEngine
 }
@@ -216,46 +431,117 @@ func From(r Router, mt *Mint) *Mint {
 }
 
 //GET register get handler
-func (mt *Mint) GET(path string, handler HandlerFunc) *HandlerContext {
-	return mt.SimpleHandler(path, http.MethodGet, handler)
+func (mt *Mint) GET(path string, handler ...HandlerFunc) *HandlerContext {
+	return mt.SimpleHandler(path, http.MethodGet, handler...)
 }
 
 //POST registers post handler
-func (mt *Mint) POST(path string, handler HandlerFunc) *HandlerContext {
-	return mt.SimpleHandler(path, http.MethodPost, handler)
+func (mt *Mint) POST(path string, handler ...HandlerFunc) *HandlerContext {
+	return mt.SimpleHandler(path, http.MethodPost, handler...)
 }
 
 //SimpleHandler registers simple handler
 func (mt *Mint) SimpleHandler(path string, method string, handler ...HandlerFunc) *HandlerContext {
+	return mt.Match([]string{method}, path, handler...)
+}
+
+//Match registers handler for multiple methods
+func (mt *Mint) Match(methods []string, path string, handler ...HandlerFunc) *HandlerContext {
+	mt.checkNotBuilt()
 	hc := new(HandlerContext)
-	hc.Methods(method)
+	hc.Methods(methods...)
 	hc.Handle(handler...)
 	hc.Path(path)
 	mt.handlers = append(mt.handlers, hc)
 	return hc
 }
 
+//PATCH registers simple PATCH handler
+func (mt *Mint) PATCH(path string, handler ...HandlerFunc) *HandlerContext {
+	return mt.SimpleHandler(path, http.MethodPatch, handler...)
+}
+
+//HEAD registers simple HEAD handler
+//GET handlers answer HEAD requests automatically, register HEAD handler
+//before GET handler of the same path to override it
+func (mt *Mint) HEAD(path string, handler ...HandlerFunc) *HandlerContext {
+	return mt.SimpleHandler(path, http.MethodHead, handler...)
+}
+
+//OPTIONS registers simple OPTIONS handler
+func (mt *Mint) OPTIONS(path string, handler ...HandlerFunc) *HandlerContext {
+	return mt.SimpleHandler(path, http.MethodOptions, handler...)
+}
+
+//Any registers handler for all methods
+func (mt *Mint) Any(path string, handler ...HandlerFunc) *HandlerContext {
+	return mt.Match(anyMethods, path, handler...)
+}
+
 //PUT register simple PUT handler
-func (mt *Mint) PUT(path string, handler HandlerFunc) *HandlerContext {
-	return mt.SimpleHandler(path, http.MethodPut, handler)
+func (mt *Mint) PUT(path string, handler ...HandlerFunc) *HandlerContext {
+	return mt.SimpleHandler(path, http.MethodPut, handler...)
 }
 
 //DELETE register simple delete handler
-func (mt *Mint) DELETE(path string, handler HandlerFunc) *HandlerContext {
-	return mt.SimpleHandler(path, http.MethodDelete, handler)
+func (mt *Mint) DELETE(path string, handler ...HandlerFunc) *HandlerContext {
+	return mt.SimpleHandler(path, http.MethodDelete, handler...)
 }
 
 //Run runs application
 func (mt *Mint) Run(serverAdd string) {
 	fmt.Println("🚀  Starting server....")
+	if mt.debug {
+		mt.PrintRoutes(os.Stdout)
+	}
 	protocal := "http"
 	localAddress := protocal + "://localhost" + serverAdd
 	fmt.Println("🌠 Ready on " + localAddress)
-	err := http.ListenAndServe(serverAdd, handlers.RecoveryHandler()(mt.Build()))
-	if err != nil {
-		fmt.Println("Stopping the server" + err.Error())
+	server := &http.Server{Addr: serverAdd, Handler: handlers.RecoveryHandler()(mt.Build())}
+	mt.serverMutex.Lock()
+	mt.server = server
+	mt.serverMutex.Unlock()
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(quit)
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- server.ListenAndServe()
+	}()
+	select {
+	case err := <-stopped:
+		if err != http.ErrServerClosed {
+			fmt.Println("Stopping the server" + err.Error())
+			mt.container.close()
+		}
+	case <-quit:
+		fmt.Println("Shutting down the server....")
+		ctx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+		defer cancel()
+		if err := mt.Shutdown(ctx); err != nil {
+			fmt.Println("Shutdown failed " + err.Error())
+		}
 	}
+}
 
+//Shutdown gracefully stops server started by Run, readiness starts failing,
+//active requests are waited for until ctx is done, then singleton services are closed
+func (mt *Mint) Shutdown(ctx context.Context) error {
+	atomic.StoreInt32(&mt.shuttingDown, 1)
+	if mt.health != nil {
+		mt.health.drain(ctx)
+	}
+	var err error
+	mt.serverMutex.Lock()
+	server := mt.server
+	mt.serverMutex.Unlock()
+	if server != nil {
+		err = server.Shutdown(ctx)
+	}
+	if closeErr := mt.container.close(); err == nil {
+		err = closeErr
+	}
+	return err
 }
 
 //URLVar formats url var
@@ -0,0 +1,464 @@
+//Package minttest provides in-process test client for mint applications.
+//Requests are served by the application router directly, without network listener
+//
+//	minttest.New(t, app).
+//		GET("/users/1").
+//		WithHeader("Authorization", "Bearer token").
+//		Expect().
+//		Status(200).
+//		JSONPath("data.name", "mint")
+//
+//Client is created by minttest.New(t, app) instead of app.Test(), since mint cannot
+//import minttest without import cycle. Client does not change the application,
+//log output and context errors are captured per request using mint.WithRequestCapture,
+//so clients of same application can be used concurrently
+package minttest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/5anthosh/mint"
+)
+
+//TestingT is subset of testing.TB used to report failed expectations
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+//Client sends requests to mint application in process
+type Client struct {
+	t       TestingT
+	app     *mint.Mint
+	logs    *logBuffer
+	headers http.Header
+}
+
+//logBuffer is concurrency safe log output of application
+type logBuffer struct {
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (lb *logBuffer) Write(data []byte) (int, error) {
+	lb.mutex.Lock()
+	defer lb.mutex.Unlock()
+	return lb.buffer.Write(data)
+}
+
+func (lb *logBuffer) String() string {
+	lb.mutex.Lock()
+	defer lb.mutex.Unlock()
+	return lb.buffer.String()
+}
+
+//New creates test client of app, application is built when first request is sent
+func New(t TestingT, app *mint.Mint) *Client {
+	return &Client{t: t, app: app, logs: new(logBuffer), headers: make(http.Header)}
+}
+
+//WithHeader sets header sent with every request of the client
+func (cl *Client) WithHeader(key, value string) *Client {
+	cl.headers.Set(key, value)
+	return cl
+}
+
+//Logs returns log output of application written while serving requests of the client
+func (cl *Client) Logs() string {
+	return cl.logs.String()
+}
+
+//Request creates request with method and path, path can contain query string
+func (cl *Client) Request(method, path string) *Request {
+	req := &Request{client: cl, method: method, path: path, header: make(http.Header), query: make(url.Values)}
+	for key, values := range cl.headers {
+		req.header[key] = append([]string(nil), values...)
+	}
+	return req
+}
+
+//GET creates GET request
+func (cl *Client) GET(path string) *Request {
+	return cl.Request(http.MethodGet, path)
+}
+
+//POST creates POST request
+func (cl *Client) POST(path string) *Request {
+	return cl.Request(http.MethodPost, path)
+}
+
+//PUT creates PUT request
+func (cl *Client) PUT(path string) *Request {
+	return cl.Request(http.MethodPut, path)
+}
+
+//PATCH creates PATCH request
+func (cl *Client) PATCH(path string) *Request {
+	return cl.Request(http.MethodPatch, path)
+}
+
+//DELETE creates DELETE request
+func (cl *Client) DELETE(path string) *Request {
+	return cl.Request(http.MethodDelete, path)
+}
+
+//HEAD creates HEAD request
+func (cl *Client) HEAD(path string) *Request {
+	return cl.Request(http.MethodHead, path)
+}
+
+//OPTIONS creates OPTIONS request
+func (cl *Client) OPTIONS(path string) *Request {
+	return cl.Request(http.MethodOptions, path)
+}
+
+//Request is request being built by the client
+type Request struct {
+	client  *Client
+	method  string
+	path    string
+	header  http.Header
+	query   url.Values
+	cookies []*http.Cookie
+	body    []byte
+	ctx     context.Context
+	err     error
+}
+
+//WithHeader sets request header
+func (r *Request) WithHeader(key, value string) *Request {
+	r.header.Set(key, value)
+	return r
+}
+
+//WithQuery adds query parameter
+func (r *Request) WithQuery(key, value string) *Request {
+	r.query.Add(key, value)
+	return r
+}
+
+//WithCookie adds cookie to request
+func (r *Request) WithCookie(cookie *http.Cookie) *Request {
+	r.cookies = append(r.cookies, cookie)
+	return r
+}
+
+//WithBasicAuth sets basic authorization header
+func (r *Request) WithBasicAuth(username, password string) *Request {
+	req := &http.Request{Header: make(http.Header)}
+	req.SetBasicAuth(username, password)
+	return r.WithHeader("Authorization", req.Header.Get("Authorization"))
+}
+
+//WithBearer sets bearer token authorization header
+func (r *Request) WithBearer(token string) *Request {
+	return r.WithHeader("Authorization", "Bearer "+token)
+}
+
+//WithContext sets context of request
+func (r *Request) WithContext(ctx context.Context) *Request {
+	r.ctx = ctx
+	return r
+}
+
+//Body sets raw request body
+func (r *Request) Body(body []byte) *Request {
+	r.body = body
+	return r
+}
+
+//StringBody sets request body
+func (r *Request) StringBody(body string) *Request {
+	return r.Body([]byte(body))
+}
+
+//JSONBody sets v encoded as JSON as request body
+func (r *Request) JSONBody(v interface{}) *Request {
+	body, err := json.Marshal(v)
+	if err != nil {
+		r.err = err
+		return r
+	}
+	if r.header.Get("Content-Type") == "" {
+		r.header.Set("Content-Type", "application/json")
+	}
+	return r.Body(body)
+}
+
+//FormBody sets URL encoded form as request body
+func (r *Request) FormBody(form url.Values) *Request {
+	if r.header.Get("Content-Type") == "" {
+		r.header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	return r.StringBody(form.Encode())
+}
+
+//Build creates http.Request
+func (r *Request) Build() *http.Request {
+	var body io.Reader
+	if r.body != nil {
+		body = bytes.NewReader(r.body)
+	}
+	req := httptest.NewRequest(r.method, r.path, body)
+	if len(r.query) > 0 {
+		query := req.URL.Query()
+		for key, values := range r.query {
+			query[key] = append(query[key], values...)
+		}
+		req.URL.RawQuery = query.Encode()
+		req.RequestURI = req.URL.RequestURI()
+	}
+	for key, values := range r.header {
+		req.Header[key] = values
+	}
+	if host := r.header.Get("Host"); host != "" {
+		req.Host = host
+	}
+	for _, cookie := range r.cookies {
+		req.AddCookie(cookie)
+	}
+	if r.ctx != nil {
+		req = req.WithContext(r.ctx)
+	}
+	return req
+}
+
+//Expect sends request and returns response for assertions
+func (r *Request) Expect() *Response {
+	r.client.t.Helper()
+	res := &Response{t: r.client.t}
+	if r.err != nil {
+		r.client.t.Errorf("minttest: %s %s: %v", r.method, r.path, r.err)
+	}
+	logs := new(logBuffer)
+	capture := &mint.RequestCapture{Log: logs}
+	req := r.Build()
+	req = req.WithContext(mint.WithRequestCapture(req.Context(), capture))
+	recorder := httptest.NewRecorder()
+	r.client.app.Build().ServeHTTP(recorder, req)
+	res.Recorder = recorder
+	res.errors = capture.Errors()
+	res.logs = logs.String()
+	r.client.logs.Write([]byte(res.logs))
+	res.name = r.method + " " + req.URL.RequestURI()
+	return res
+}
+
+//Response is recorded response of request
+type Response struct {
+	t        TestingT
+	name     string
+	Recorder *httptest.ResponseRecorder
+	errors   []error
+	logs     string
+	json     interface{}
+	jsonErr  error
+	decoded  bool
+}
+
+//Code returns status code of response
+func (r *Response) Code() int {
+	return r.Recorder.Code
+}
+
+//Header returns response headers
+func (r *Response) Header() http.Header {
+	return r.Recorder.Header()
+}
+
+//Body returns response body
+func (r *Response) Body() string {
+	return r.Recorder.Body.String()
+}
+
+//Errors returns errors recorded in context while serving request
+func (r *Response) Errors() []error {
+	return r.errors
+}
+
+//Logs returns log output of application written while serving request
+func (r *Response) Logs() string {
+	return r.logs
+}
+
+//Decode decodes JSON response body into v
+func (r *Response) Decode(v interface{}) error {
+	return json.Unmarshal(r.Recorder.Body.Bytes(), v)
+}
+
+//Status expects status code of response
+func (r *Response) Status(code int) *Response {
+	r.t.Helper()
+	if r.Recorder.Code != code {
+		r.t.Errorf("minttest: %s: expected status %d, got %d, body %s", r.name, code, r.Recorder.Code, r.Body())
+	}
+	return r
+}
+
+//HeaderEqual expects response header to have value
+func (r *Response) HeaderEqual(key, value string) *Response {
+	r.t.Helper()
+	if actual := r.Recorder.Header().Get(key); actual != value {
+		r.t.Errorf("minttest: %s: expected header %s %q, got %q", r.name, key, value, actual)
+	}
+	return r
+}
+
+//HeaderPresent expects response to have header
+func (r *Response) HeaderPresent(key string) *Response {
+	r.t.Helper()
+	if _, ok := r.Recorder.Header()[http.CanonicalHeaderKey(key)]; !ok {
+		r.t.Errorf("minttest: %s: expected header %s", r.name, key)
+	}
+	return r
+}
+
+//BodyEqual expects response body
+func (r *Response) BodyEqual(body string) *Response {
+	r.t.Helper()
+	if actual := r.Body(); actual != body {
+		r.t.Errorf("minttest: %s: expected body %q, got %q", r.name, body, actual)
+	}
+	return r
+}
+
+//BodyContains expects response body to contain substr
+func (r *Response) BodyContains(substr string) *Response {
+	r.t.Helper()
+	if !strings.Contains(r.Body(), substr) {
+		r.t.Errorf("minttest: %s: expected body to contain %q, got %q", r.name, substr, r.Body())
+	}
+	return r
+}
+
+//JSON expects JSON response body to be equal to v encoded as JSON
+func (r *Response) JSON(v interface{}) *Response {
+	r.t.Helper()
+	actual, err := r.decodedJSON()
+	if err != nil {
+		r.t.Errorf("minttest: %s: invalid JSON body %q: %v", r.name, r.Body(), err)
+		return r
+	}
+	expected, err := normalizeJSON(v)
+	if err != nil {
+		r.t.Errorf("minttest: %s: %v", r.name, err)
+		return r
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		r.t.Errorf("minttest: %s: expected JSON %v, got %v", r.name, expected, actual)
+	}
+	return r
+}
+
+//JSONPath expects value at path of JSON response body to be equal to v.
+//Path is dot separated keys and array indexes such as data.items.0.name,
+//data.items[0].name and $.data.items[0].name are accepted too
+func (r *Response) JSONPath(path string, v interface{}) *Response {
+	r.t.Helper()
+	actual, err := r.decodedJSON()
+	if err != nil {
+		r.t.Errorf("minttest: %s: invalid JSON body %q: %v", r.name, r.Body(), err)
+		return r
+	}
+	actual, err = lookupJSONPath(actual, path)
+	if err != nil {
+		r.t.Errorf("minttest: %s: %v", r.name, err)
+		return r
+	}
+	expected, err := normalizeJSON(v)
+	if err != nil {
+		r.t.Errorf("minttest: %s: %v", r.name, err)
+		return r
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		r.t.Errorf("minttest: %s: expected %v at %s, got %v", r.name, expected, path, actual)
+	}
+	return r
+}
+
+//NoErrors expects no errors recorded in context
+func (r *Response) NoErrors() *Response {
+	r.t.Helper()
+	if len(r.errors) > 0 {
+		r.t.Errorf("minttest: %s: expected no errors, got %v", r.name, r.errors)
+	}
+	return r
+}
+
+//ErrorContains expects error containing substr recorded in context
+func (r *Response) ErrorContains(substr string) *Response {
+	r.t.Helper()
+	for _, err := range r.errors {
+		if strings.Contains(err.Error(), substr) {
+			return r
+		}
+	}
+	r.t.Errorf("minttest: %s: expected error containing %q, got %v", r.name, substr, r.errors)
+	return r
+}
+
+//LogContains expects log output of request to contain substr
+func (r *Response) LogContains(substr string) *Response {
+	r.t.Helper()
+	if !strings.Contains(r.logs, substr) {
+		r.t.Errorf("minttest: %s: expected log to contain %q, got %q", r.name, substr, r.logs)
+	}
+	return r
+}
+
+func (r *Response) decodedJSON() (interface{}, error) {
+	if !r.decoded {
+		r.jsonErr = json.Unmarshal(r.Recorder.Body.Bytes(), &r.json)
+		r.decoded = true
+	}
+	return r.json, r.jsonErr
+}
+
+//normalizeJSON converts v to value decoded from JSON, so numbers are float64
+func normalizeJSON(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var normalized interface{}
+	err = json.Unmarshal(data, &normalized)
+	return normalized, err
+}
+
+func lookupJSONPath(value interface{}, path string) (interface{}, error) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	path = strings.Replace(strings.Replace(path, "[", ".", -1), "]", "", -1)
+	if path == "" {
+		return value, nil
+	}
+	for _, key := range strings.Split(path, ".") {
+		switch node := value.(type) {
+		case map[string]interface{}:
+			child, ok := node[key]
+			if !ok {
+				return nil, fmt.Errorf("JSON path %s: key %s not found", path, key)
+			}
+			value = child
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, fmt.Errorf("JSON path %s: invalid index %s of array of length %d", path, key, len(node))
+			}
+			value = node[index]
+		default:
+			return nil, fmt.Errorf("JSON path %s: %s is not object or array", path, key)
+		}
+	}
+	return value, nil
+}
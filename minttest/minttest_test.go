@@ -0,0 +1,103 @@
+package minttest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/5anthosh/mint"
+)
+
+//fakeT records failed expectations
+type fakeT struct {
+	failures []string
+}
+
+func (ft *fakeT) Helper() {}
+
+func (ft *fakeT) Errorf(format string, args ...interface{}) {
+	ft.failures = append(ft.failures, fmt.Sprintf(format, args...))
+}
+
+func testApp() *mint.Mint {
+	app := mint.New()
+	app.GET("/users/{id}", func(c *mint.Context) {
+		id, _ := c.Param("id")
+		c.SetHeader("X-User", []string{id})
+		c.JSON(http.StatusOK, map[string]interface{}{
+			"data": map[string]interface{}{"id": id, "tags": []string{"a", "b"}},
+		})
+	})
+	app.POST("/echo", func(c *mint.Context) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(c.Req.Body).Decode(&body); err != nil {
+			c.Error(err)
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		c.JSON(http.StatusCreated, body)
+	})
+	app.GET("/fail", func(c *mint.Context) {
+		c.Error(errors.New("database is down"))
+		c.Status(http.StatusInternalServerError)
+	})
+	return app
+}
+
+func TestClientExpectations(t *testing.T) {
+	client := New(t, testApp())
+	client.GET("/users/7").
+		Expect().
+		Status(http.StatusOK).
+		HeaderEqual("X-User", "7").
+		HeaderPresent("Content-Type").
+		JSONPath("data.id", "7").
+		JSONPath("$.data.tags[1]", "b").
+		NoErrors()
+	client.POST("/echo").
+		JSONBody(map[string]int{"n": 1}).
+		Expect().
+		Status(http.StatusCreated).
+		JSON(map[string]int{"n": 1})
+	client.GET("/missing").Expect().Status(http.StatusNotFound)
+}
+
+func TestClientCapturesErrorsAndLogs(t *testing.T) {
+	app := testApp()
+	first := New(t, app)
+	second := New(t, app)
+	first.GET("/fail").
+		Expect().
+		Status(http.StatusInternalServerError).
+		ErrorContains("database is down").
+		LogContains("/fail")
+	second.GET("/users/1").Expect().Status(http.StatusOK).NoErrors()
+	if logs := first.Logs(); !strings.Contains(logs, "/fail") || strings.Contains(logs, "/users/1") {
+		t.Errorf("logs of first client = %q, want only its request", logs)
+	}
+	if logs := second.Logs(); strings.Contains(logs, "/fail") {
+		t.Errorf("logs of second client = %q, want only its request", logs)
+	}
+	//client does not change application, so it can be created after build
+	New(t, app).GET("/users/2").Expect().Status(http.StatusOK)
+}
+
+func TestFailedExpectations(t *testing.T) {
+	ft := new(fakeT)
+	New(ft, testApp()).GET("/users/7").
+		Expect().
+		Status(http.StatusCreated).
+		HeaderEqual("X-User", "8").
+		JSONPath("data.id", "8").
+		JSONPath("data.missing", 1).
+		ErrorContains("anything")
+	if len(ft.failures) != 5 {
+		t.Fatalf("failures = %q, want 5", ft.failures)
+	}
+	if !strings.Contains(ft.failures[0], "expected status 201, got 200") {
+		t.Errorf("failure = %q, want status failure", ft.failures[0])
+	}
+}
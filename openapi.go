@@ -0,0 +1,472 @@
+package mint
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const openAPIVersion = "3.0.3"
+
+//OpenAPISpec is OpenAPI 3 document
+type OpenAPISpec struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       OpenAPIInfo                             `json:"info"`
+	Paths      map[string]map[string]*OpenAPIOperation `json:"paths"`
+	Components *OpenAPIComponents                      `json:"components,omitempty"`
+}
+
+//OpenAPIInfo is metadata of API
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+//OpenAPIOperation is single API operation on a path
+type OpenAPIOperation struct {
+	OperationID string                      `json:"operationId,omitempty"`
+	Summary     string                      `json:"summary,omitempty"`
+	Description string                      `json:"description,omitempty"`
+	Tags        []string                    `json:"tags,omitempty"`
+	Parameters  []*OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*OpenAPIResponse `json:"responses"`
+	Deprecated  bool                        `json:"deprecated,omitempty"`
+}
+
+//OpenAPIParameter is path, query or header parameter
+type OpenAPIParameter struct {
+	Name     string         `json:"name"`
+	In       string         `json:"in"`
+	Required bool           `json:"required,omitempty"`
+	Schema   *OpenAPISchema `json:"schema,omitempty"`
+}
+
+//OpenAPIRequestBody is request body of operation
+type OpenAPIRequestBody struct {
+	Required bool                         `json:"required,omitempty"`
+	Content  map[string]*OpenAPIMediaType `json:"content"`
+}
+
+//OpenAPIResponse is response of operation
+type OpenAPIResponse struct {
+	Description string                       `json:"description"`
+	Content     map[string]*OpenAPIMediaType `json:"content,omitempty"`
+}
+
+//OpenAPIMediaType is schema of content
+type OpenAPIMediaType struct {
+	Schema *OpenAPISchema `json:"schema,omitempty"`
+}
+
+//OpenAPIComponents contains reusable schemas
+type OpenAPIComponents struct {
+	Schemas map[string]*OpenAPISchema `json:"schemas,omitempty"`
+}
+
+//OpenAPISchema is JSON schema of a type
+type OpenAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Pattern              string                    `json:"pattern,omitempty"`
+	Nullable             bool                      `json:"nullable,omitempty"`
+	Items                *OpenAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*OpenAPISchema `json:"properties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	AdditionalProperties *OpenAPISchema            `json:"additionalProperties,omitempty"`
+	Enum                 []string                  `json:"enum,omitempty"`
+}
+
+//apiDoc documents handler in OpenAPI spec
+type apiDoc struct {
+	summary     string
+	description string
+	tags        []string
+	request     reflect.Type
+	responses   map[int]reflect.Type
+	hidden      bool
+}
+
+func (hc *HandlerContext) apiDoc() *apiDoc {
+	if hc.doc == nil {
+		hc.doc = &apiDoc{responses: make(map[int]reflect.Type)}
+	}
+	return hc.doc
+}
+
+//Summary documents summary of the handler
+func (hc *HandlerContext) Summary(summary string) *HandlerContext {
+	if hc == nil {
+		return hc
+	}
+	hc.apiDoc().summary = summary
+	return hc
+}
+
+//Description documents description of the handler
+func (hc *HandlerContext) Description(description string) *HandlerContext {
+	if hc == nil {
+		return hc
+	}
+	hc.apiDoc().description = description
+	return hc
+}
+
+//Tags documents tags of the handler
+func (hc *HandlerContext) Tags(tags ...string) *HandlerContext {
+	if hc == nil {
+		return hc
+	}
+	hc.apiDoc().tags = append(hc.apiDoc().tags, tags...)
+	return hc
+}
+
+//Request documents type of JSON request body, v is value of the type
+func (hc *HandlerContext) Request(v interface{}) *HandlerContext {
+	if hc == nil {
+		return hc
+	}
+	hc.apiDoc().request = reflect.TypeOf(v)
+	return hc
+}
+
+//Response documents type of JSON response for status code, v can be nil for empty body
+func (hc *HandlerContext) Response(code int, v interface{}) *HandlerContext {
+	if hc == nil {
+		return hc
+	}
+	hc.apiDoc().responses[code] = reflect.TypeOf(v)
+	return hc
+}
+
+//Undocumented excludes the handler from OpenAPI spec
+func (hc *HandlerContext) Undocumented() *HandlerContext {
+	if hc == nil {
+		return hc
+	}
+	hc.apiDoc().hidden = true
+	return hc
+}
+
+//OpenAPIInfo sets title and version of API in OpenAPI spec
+func (mt *Mint) OpenAPIInfo(info OpenAPIInfo) *Mint {
+	mt.openAPIInfo = info
+	return mt
+}
+
+//OpenAPI generates OpenAPI spec from registered routes. Route matching any method
+//is documented for each method. Versions selected by header or media type share
+//path, so operation of the newest version having the route is documented
+func (mt *Mint) OpenAPI() *OpenAPISpec {
+	app := mt.snapshot()
+	spec := &OpenAPISpec{
+		OpenAPI: openAPIVersion,
+		Info:    mt.openAPIInfo,
+		Paths:   make(map[string]map[string]*OpenAPIOperation),
+	}
+	if spec.Info.Title == emptyString {
+		spec.Info.Title = "API"
+	}
+	if spec.Info.Version == emptyString {
+		spec.Info.Version = "1.0.0"
+	}
+	generator := &schemaGenerator{schemas: make(map[string]*OpenAPISchema), names: make(map[reflect.Type]string)}
+	versioned := make(map[*OpenAPIOperation]*versionedOperation)
+	for _, hc := range app.routes {
+		if (hc.doc != nil && hc.doc.hidden) || (hc.group != nil && hc.group.prefixHandler == hc) {
+			continue
+		}
+		route := hc.routeInfo()
+		path, params := openAPIPath(route.Path)
+		for iter := 0; iter+1 < len(route.Queries); iter += 2 {
+			params = append(params, &OpenAPIParameter{
+				Name:     route.Queries[iter],
+				In:       "query",
+				Required: true,
+				Schema:   &OpenAPISchema{Type: "string"},
+			})
+		}
+		item, ok := spec.Paths[path]
+		if !ok {
+			item = make(map[string]*OpenAPIOperation)
+			spec.Paths[path] = item
+		}
+		versions, index := app.routeVersion(hc)
+		for _, method := range hc.openAPIMethods() {
+			key := strings.ToLower(method)
+			existing, ok := item[key]
+			if ok && (versions == nil || versioned[existing] == nil) {
+				//first registered route is matched
+				continue
+			}
+			operation := hc.openAPIOperation(generator, params)
+			if versions == nil {
+				item[key] = operation
+				continue
+			}
+			entry := versioned[existing]
+			if entry == nil {
+				entry = &versionedOperation{versions: versions}
+			}
+			entry.indexes = append(entry.indexes, index)
+			if !ok || index > entry.newest {
+				delete(versioned, existing)
+				entry.newest = index
+				item[key] = operation
+				versioned[operation] = entry
+			}
+		}
+	}
+	for operation, entry := range versioned {
+		entry.document(operation)
+	}
+	if len(generator.schemas) > 0 {
+		spec.Components = &OpenAPIComponents{Schemas: generator.schemas}
+	}
+	return spec
+}
+
+//openAPIMethods returns methods documented for handler, automatic HEAD
+//of GET handler is not documented and CONNECT is not OpenAPI operation
+func (hc *HandlerContext) openAPIMethods() []string {
+	methods := hc.effectiveMethods()
+	if len(methods) == 0 {
+		methods = anyMethods
+	}
+	documented := make([]string, 0, len(methods))
+	for _, method := range methods {
+		if strings.EqualFold(method, http.MethodConnect) || (hc.autoHead && strings.EqualFold(method, http.MethodHead)) {
+			continue
+		}
+		documented = append(documented, method)
+	}
+	return documented
+}
+
+//versionedOperation is operation shared by versions selected by header or media type
+type versionedOperation struct {
+	versions *Versions
+	indexes  []int
+	newest   int
+}
+
+//document adds version header parameter listing versions having the operation
+func (vo *versionedOperation) document(operation *OpenAPIOperation) {
+	if vo.versions.config.Strategy != VersionByHeader {
+		return
+	}
+	sort.Ints(vo.indexes)
+	names := make([]string, 0, len(vo.indexes))
+	for _, index := range vo.indexes {
+		names = append(names, vo.versions.versions[index].name)
+	}
+	operation.Parameters = append(append([]*OpenAPIParameter(nil), operation.Parameters...), &OpenAPIParameter{
+		Name:   vo.versions.config.Header,
+		In:     "header",
+		Schema: &OpenAPISchema{Type: "string", Enum: names},
+	})
+}
+
+//routeVersion returns versions and index of version whose group contains handler,
+//versions is nil when handler is not versioned by header or media type
+func (mt *Mint) routeVersion(hc *HandlerContext) (*Versions, int) {
+	for group := hc.group; group != nil; group = group.parent {
+		for _, vs := range mt.versions {
+			if vs.config.Strategy == VersionByPath {
+				continue
+			}
+			for index, version := range vs.versions {
+				if version.group == group {
+					return vs, index
+				}
+			}
+		}
+	}
+	return nil, 0
+}
+
+func (hc *HandlerContext) openAPIOperation(generator *schemaGenerator, params []*OpenAPIParameter) *OpenAPIOperation {
+	operation := &OpenAPIOperation{
+		OperationID: hc.name,
+		Parameters:  params,
+		Responses:   make(map[string]*OpenAPIResponse),
+	}
+	doc := hc.doc
+	if doc == nil {
+		operation.Responses["default"] = &OpenAPIResponse{Description: "Response"}
+		return operation
+	}
+	operation.Summary = doc.summary
+	operation.Description = doc.description
+	operation.Tags = doc.tags
+	if doc.request != nil {
+		operation.RequestBody = &OpenAPIRequestBody{
+			Required: true,
+			Content:  jsonContent(generator.schema(doc.request)),
+		}
+	}
+	for code, typ := range doc.responses {
+		response := &OpenAPIResponse{Description: http.StatusText(code)}
+		if typ != nil {
+			response.Content = jsonContent(generator.schema(typ))
+		}
+		operation.Responses[strconv.Itoa(code)] = response
+	}
+	if len(operation.Responses) == 0 {
+		operation.Responses["default"] = &OpenAPIResponse{Description: "Response"}
+	}
+	return operation
+}
+
+func jsonContent(schema *OpenAPISchema) map[string]*OpenAPIMediaType {
+	return map[string]*OpenAPIMediaType{"application/json": {Schema: schema}}
+}
+
+//openAPIPath converts route template to OpenAPI path and path parameters
+//for example /users/{id:[0-9]+} becomes /users/{id}
+func openAPIPath(tpl string) (string, []*OpenAPIParameter) {
+	var params []*OpenAPIParameter
+	var path strings.Builder
+	last := 0
+	for _, variable := range parseTemplate(tpl) {
+		path.WriteString(tpl[last:variable.start])
+		path.WriteString(URLVar(variable.name))
+		last = variable.end
+		schema := &OpenAPISchema{Type: "string"}
+		switch variable.pattern {
+		case emptyString:
+		case "[0-9]+", "-?[0-9]+", `\d+`:
+			schema = &OpenAPISchema{Type: "integer"}
+		default:
+			schema.Pattern = "^" + variable.pattern + "$"
+		}
+		params = append(params, &OpenAPIParameter{
+			Name:     variable.name,
+			In:       "path",
+			Required: true,
+			Schema:   schema,
+		})
+	}
+	path.WriteString(tpl[last:])
+	return path.String(), params
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+//schemaGenerator reflects Go types into schemas,
+//named struct types are added to components and referred
+type schemaGenerator struct {
+	schemas map[string]*OpenAPISchema
+	names   map[reflect.Type]string
+}
+
+func (generator *schemaGenerator) schema(typ reflect.Type) *OpenAPISchema {
+	if typ == timeType {
+		return &OpenAPISchema{Type: "string", Format: "date-time"}
+	}
+	switch typ.Kind() {
+	case reflect.Ptr:
+		schema := generator.schema(typ.Elem())
+		if schema.Ref == emptyString {
+			schema.Nullable = true
+		}
+		return schema
+	case reflect.Bool:
+		return &OpenAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &OpenAPISchema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &OpenAPISchema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &OpenAPISchema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &OpenAPISchema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &OpenAPISchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return &OpenAPISchema{Type: "string", Format: "byte"}
+		}
+		return &OpenAPISchema{Type: "array", Items: generator.schema(typ.Elem())}
+	case reflect.Map:
+		return &OpenAPISchema{Type: "object", AdditionalProperties: generator.schema(typ.Elem())}
+	case reflect.Struct:
+		if typ.Name() == emptyString {
+			return generator.structSchema(typ)
+		}
+		return generator.ref(typ)
+	}
+	return &OpenAPISchema{}
+}
+
+func (generator *schemaGenerator) ref(typ reflect.Type) *OpenAPISchema {
+	name, ok := generator.names[typ]
+	if !ok {
+		name = typ.Name()
+		for iter := 2; generator.schemas[name] != nil; iter++ {
+			name = typ.Name() + strconv.Itoa(iter)
+		}
+		generator.names[typ] = name
+		//placeholder stops recursion of self referencing types
+		generator.schemas[name] = &OpenAPISchema{}
+		*generator.schemas[name] = *generator.structSchema(typ)
+	}
+	return &OpenAPISchema{Ref: "#/components/schemas/" + name}
+}
+
+func (generator *schemaGenerator) structSchema(typ reflect.Type) *OpenAPISchema {
+	schema := &OpenAPISchema{Type: "object", Properties: make(map[string]*OpenAPISchema)}
+	generator.addFields(schema, typ)
+	return schema
+}
+
+func (generator *schemaGenerator) addFields(schema *OpenAPISchema, typ reflect.Type) {
+	for iter := 0; iter < typ.NumField(); iter++ {
+		field := typ.Field(iter)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		name := parts[0]
+		if field.Anonymous && name == emptyString {
+			fieldType := field.Type
+			if fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct {
+				generator.addFields(schema, fieldType)
+				continue
+			}
+		}
+		if field.PkgPath != emptyString {
+			continue
+		}
+		if name == emptyString {
+			name = field.Name
+		}
+		schema.Properties[name] = generator.schema(field.Type)
+		if !containsString(parts[1:], "omitempty") && field.Type.Kind() != reflect.Ptr {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+//ServeOpenAPI serves OpenAPI spec as JSON at path
+func (mt *Mint) ServeOpenAPI(path string) *HandlerContext {
+	var once sync.Once
+	var spec *OpenAPISpec
+	return mt.GET(path, func(c *Context) {
+		once.Do(func() {
+			spec = mt.OpenAPI()
+		})
+		c.JSON(http.StatusOK, spec)
+	}).Undocumented()
+}
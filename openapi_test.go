@@ -0,0 +1,51 @@
+package mint
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestOpenAPIGroupFilters(t *testing.T) {
+	mt := Simple()
+	group := mt.Group("/g").Methods(http.MethodGet).Queries("q", "{q}")
+	group.Handler(HandlerBuilder().Path("/x").Handle(func(c *Context) {}))
+	mt.Any("/any", func(c *Context) {})
+	spec := mt.OpenAPI()
+	operation := spec.Paths["/g/x"]["get"]
+	if operation == nil {
+		t.Fatalf("Paths[/g/x] = %v, want get operation", spec.Paths["/g/x"])
+	}
+	if len(operation.Parameters) != 1 || operation.Parameters[0].Name != "q" || operation.Parameters[0].In != "query" {
+		t.Errorf("Parameters = %v, want query parameter q", operation.Parameters)
+	}
+	if _, ok := spec.Paths["/g/x"]["head"]; ok {
+		t.Error("automatic HEAD is documented")
+	}
+	if count := len(spec.Paths["/any"]); count != len(anyMethods)-1 {
+		t.Errorf("Any route has %d operations, want %d", count, len(anyMethods)-1)
+	}
+	//spec does not build application
+	mt.GET("/after", func(c *Context) {})
+}
+
+func TestOpenAPIHeaderVersions(t *testing.T) {
+	mt := Simple()
+	versions := mt.Versions(VersionConfig{Strategy: VersionByHeader, Prefix: "/api"})
+	versions.Version("v1").GET("/users", func(c *Context) {}).Summary("old")
+	versions.Version("v2").GET("/users", func(c *Context) {}).Summary("new")
+	operation := mt.OpenAPI().Paths["/api/users"]["get"]
+	if operation == nil || operation.Summary != "new" {
+		t.Fatalf("operation = %+v, want operation of newest version", operation)
+	}
+	if len(operation.Parameters) != 1 {
+		t.Fatalf("Parameters = %v, want version header", operation.Parameters)
+	}
+	header := operation.Parameters[0]
+	if header.Name != DefaultVersionHeader || header.In != "header" {
+		t.Errorf("parameter = %+v, want %s header", header, DefaultVersionHeader)
+	}
+	if want := []string{"v1", "v2"}; !reflect.DeepEqual(header.Schema.Enum, want) {
+		t.Errorf("Enum = %v, want %v", header.Schema.Enum, want)
+	}
+}
@@ -0,0 +1,326 @@
+package mint
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//ParamError is error of converting parameter
+type ParamError struct {
+	Key   string
+	Value string
+	Err   error
+}
+
+func (pe *ParamError) Error() string {
+	if pe.Err == nil {
+		return "mint: parameter " + pe.Key + " not found"
+	}
+	return fmt.Sprintf("mint: parameter %s has invalid value %q: %v", pe.Key, pe.Value, pe.Err)
+}
+
+//UUID is universally unique identifier
+type UUID [16]byte
+
+//ParseUUID parses UUID in canonical form xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx
+func ParseUUID(value string) (UUID, error) {
+	var uuid UUID
+	if len(value) != 36 || value[8] != '-' || value[13] != '-' || value[18] != '-' || value[23] != '-' {
+		return uuid, fmt.Errorf("invalid UUID format")
+	}
+	//segments are decoded separately, so dashes cannot take place of hex digits
+	segments := [][2]int{{0, 8}, {9, 13}, {14, 18}, {19, 23}, {24, 36}}
+	offset := 0
+	for _, segment := range segments {
+		size, err := hex.Decode(uuid[offset:], []byte(value[segment[0]:segment[1]]))
+		if err != nil {
+			return UUID{}, fmt.Errorf("invalid UUID format")
+		}
+		offset += size
+	}
+	return uuid, nil
+}
+
+//String returns UUID in canonical form
+func (uuid UUID) String() string {
+	buf := make([]byte, 36)
+	hex.Encode(buf[0:8], uuid[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], uuid[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], uuid[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], uuid[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], uuid[10:])
+	return string(buf)
+}
+
+//ConvertFunc converts path parameter to typed value
+type ConvertFunc func(value string) (interface{}, error)
+
+type converter struct {
+	pattern string
+	convert ConvertFunc
+}
+
+//registerDefaultConverters registers int, int64, float and uuid converters
+func (mt *Mint) registerDefaultConverters() {
+	mt.Converter("int", "-?[0-9]+", func(value string) (interface{}, error) {
+		return strconv.Atoi(value)
+	})
+	mt.Converter("int64", "-?[0-9]+", func(value string) (interface{}, error) {
+		return strconv.ParseInt(value, 10, 64)
+	})
+	mt.Converter("float", `-?[0-9]+(?:\.[0-9]+)?`, func(value string) (interface{}, error) {
+		return strconv.ParseFloat(value, 64)
+	})
+	mt.Converter("uuid", "[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}", func(value string) (interface{}, error) {
+		return ParseUUID(value)
+	})
+}
+
+//Converter registers path parameter converter, {id:name} in route template
+//is matched by pattern and converted once per request, converted value
+//is available using c.ParamValue and typed param methods
+func (mt *Mint) Converter(name string, pattern string, convert ConvertFunc) *Mint {
+	mt.checkNotBuilt()
+	if mt.converters == nil {
+		mt.converters = make(map[string]*converter)
+	}
+	mt.converters[name] = &converter{pattern: pattern, convert: convert}
+	return mt
+}
+
+//applyConverters replaces converter names in template with their patterns
+//and adds converters of variables to converters
+func (mt *Mint) applyConverters(tpl string, converters map[string]*converter) (string, map[string]*converter) {
+	vars := parseTemplate(tpl)
+	if len(vars) == 0 || len(mt.converters) == 0 {
+		return tpl, converters
+	}
+	var rewritten strings.Builder
+	last := 0
+	for _, variable := range vars {
+		conv, ok := mt.converters[variable.pattern]
+		if !ok {
+			continue
+		}
+		rewritten.WriteString(tpl[last:variable.start])
+		rewritten.WriteString("{" + variable.name + ":" + conv.pattern + "}")
+		last = variable.end
+		if converters == nil {
+			converters = make(map[string]*converter)
+		}
+		converters[variable.name] = conv
+	}
+	rewritten.WriteString(tpl[last:])
+	return rewritten.String(), converters
+}
+
+//inheritConverters returns converters of group merged with converters
+func inheritConverters(group map[string]*converter, converters map[string]*converter) map[string]*converter {
+	if len(group) == 0 {
+		return converters
+	}
+	merged := make(map[string]*converter, len(group)+len(converters))
+	for name, conv := range group {
+		merged[name] = conv
+	}
+	for name, conv := range converters {
+		merged[name] = conv
+	}
+	return merged
+}
+
+//convertParams converts parameters having converters
+//conversion errors are recorded and value is left unset
+func (c *Context) convertParams() {
+	converters := c.HandlerContext.converters
+	if len(converters) == 0 {
+		return
+	}
+	c.paramValues = make(map[string]interface{}, len(converters))
+	for name, conv := range converters {
+		value, ok := c.params[name]
+		if !ok {
+			continue
+		}
+		converted, err := conv.convert(value)
+		if err != nil {
+			c.Error(&ParamError{Key: name, Value: value, Err: err})
+			continue
+		}
+		c.paramValues[name] = converted
+	}
+}
+
+//ParamValue returns converted value of path parameter
+func (c *Context) ParamValue(key string) (interface{}, bool) {
+	value, ok := c.paramValues[key]
+	return value, ok
+}
+
+func (c *Context) param(key string) (string, error) {
+	value, ok := c.params[key]
+	if !ok {
+		return emptyString, &ParamError{Key: key}
+	}
+	return value, nil
+}
+
+func (c *Context) queryParam(key string) (string, error) {
+	value, ok := c.Query(key)
+	if !ok {
+		return emptyString, &ParamError{Key: key}
+	}
+	return value, nil
+}
+
+//ParamInt returns path parameter as int
+func (c *Context) ParamInt(key string) (int, error) {
+	if value, ok := c.paramValues[key].(int); ok {
+		return value, nil
+	}
+	value, err := c.param(key)
+	if err != nil {
+		return 0, err
+	}
+	return parseInt(key, value)
+}
+
+//ParamInt64 returns path parameter as int64
+func (c *Context) ParamInt64(key string) (int64, error) {
+	if value, ok := c.paramValues[key].(int64); ok {
+		return value, nil
+	}
+	value, err := c.param(key)
+	if err != nil {
+		return 0, err
+	}
+	return parseInt64(key, value)
+}
+
+//ParamFloat64 returns path parameter as float64
+func (c *Context) ParamFloat64(key string) (float64, error) {
+	if value, ok := c.paramValues[key].(float64); ok {
+		return value, nil
+	}
+	value, err := c.param(key)
+	if err != nil {
+		return 0, err
+	}
+	return parseFloat64(key, value)
+}
+
+//ParamUUID returns path parameter as UUID
+func (c *Context) ParamUUID(key string) (UUID, error) {
+	if value, ok := c.paramValues[key].(UUID); ok {
+		return value, nil
+	}
+	value, err := c.param(key)
+	if err != nil {
+		return UUID{}, err
+	}
+	return parseUUID(key, value)
+}
+
+//ParamTime returns path parameter as time parsed using layout
+func (c *Context) ParamTime(key string, layout string) (time.Time, error) {
+	if value, ok := c.paramValues[key].(time.Time); ok {
+		return value, nil
+	}
+	value, err := c.param(key)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return parseTime(key, layout, value)
+}
+
+//QueryInt returns query parameter as int
+func (c *Context) QueryInt(key string) (int, error) {
+	value, err := c.queryParam(key)
+	if err != nil {
+		return 0, err
+	}
+	return parseInt(key, value)
+}
+
+//QueryInt64 returns query parameter as int64
+func (c *Context) QueryInt64(key string) (int64, error) {
+	value, err := c.queryParam(key)
+	if err != nil {
+		return 0, err
+	}
+	return parseInt64(key, value)
+}
+
+//QueryFloat64 returns query parameter as float64
+func (c *Context) QueryFloat64(key string) (float64, error) {
+	value, err := c.queryParam(key)
+	if err != nil {
+		return 0, err
+	}
+	return parseFloat64(key, value)
+}
+
+//QueryUUID returns query parameter as UUID
+func (c *Context) QueryUUID(key string) (UUID, error) {
+	value, err := c.queryParam(key)
+	if err != nil {
+		return UUID{}, err
+	}
+	return parseUUID(key, value)
+}
+
+//QueryTime returns query parameter as time parsed using layout
+func (c *Context) QueryTime(key string, layout string) (time.Time, error) {
+	value, err := c.queryParam(key)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return parseTime(key, layout, value)
+}
+
+func parseInt(key string, value string) (int, error) {
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, &ParamError{Key: key, Value: value, Err: err}
+	}
+	return parsed, nil
+}
+
+func parseInt64(key string, value string) (int64, error) {
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, &ParamError{Key: key, Value: value, Err: err}
+	}
+	return parsed, nil
+}
+
+func parseFloat64(key string, value string) (float64, error) {
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, &ParamError{Key: key, Value: value, Err: err}
+	}
+	return parsed, nil
+}
+
+func parseUUID(key string, value string) (UUID, error) {
+	parsed, err := ParseUUID(value)
+	if err != nil {
+		return UUID{}, &ParamError{Key: key, Value: value, Err: err}
+	}
+	return parsed, nil
+}
+
+func parseTime(key string, layout string, value string) (time.Time, error) {
+	parsed, err := time.Parse(layout, value)
+	if err != nil {
+		return time.Time{}, &ParamError{Key: key, Value: value, Err: err}
+	}
+	return parsed, nil
+}
@@ -0,0 +1,235 @@
+package mint
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+//RateLimit headers
+const (
+	headerRateLimitLimit     = "RateLimit-Limit"
+	headerRateLimitRemaining = "RateLimit-Remaining"
+	headerRateLimitReset     = "RateLimit-Reset"
+	headerRetryAfter         = "Retry-After"
+)
+
+//RateLimitAlgorithm is algorithm used to limit requests
+type RateLimitAlgorithm int
+
+//Rate limit algorithms
+const (
+	//TokenBucket refills tokens continuously and allows bursts up to bucket size
+	TokenBucket RateLimitAlgorithm = iota
+	//SlidingWindow counts requests in the window sliding over current time
+	SlidingWindow
+)
+
+//RateLimitRule describes how many requests are allowed for a key
+type RateLimitRule struct {
+	Algorithm RateLimitAlgorithm
+	//Limit is number of requests allowed in Window
+	Limit  int
+	Window time.Duration
+	//Burst is size of the token bucket, default is Limit
+	Burst int
+}
+
+//RateLimitResult is result of taking a request from the store
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	//Reset is time until the limit is fully restored
+	Reset time.Duration
+	//RetryAfter is time until next request is allowed, when it is not allowed
+	RetryAfter time.Duration
+}
+
+//RateLimitStore keeps rate limit state of keys
+//Implement it to share limits between multiple instances
+type RateLimitStore interface {
+	Take(key string, rule RateLimitRule) (RateLimitResult, error)
+}
+
+//RateLimitConfig configures rate limit middleware
+type RateLimitConfig struct {
+	RateLimitRule
+	//KeyFunc returns key to limit the request by, default is c.ClientIP()
+	KeyFunc func(*Context) string
+	//Store keeps the state, default is in memory store
+	Store RateLimitStore
+}
+
+//RateLimit creates rate limit middleware. Default key is c.ClientIP(), which trusts
+//X-Forwarded-For header, so clients can choose their key unless trusted proxy overwrites it
+func RateLimit(config RateLimitConfig) HandlerFunc {
+	rule := config.RateLimitRule
+	if rule.Limit <= 0 {
+		rule.Limit = 1
+	}
+	if rule.Window <= 0 {
+		rule.Window = time.Second
+	}
+	if rule.Burst <= 0 {
+		rule.Burst = rule.Limit
+	}
+	keyFunc := config.KeyFunc
+	if keyFunc == nil {
+		keyFunc = clientIPKey
+	}
+	store := config.Store
+	if store == nil {
+		store = NewMemoryRateLimitStore()
+	}
+	return func(c *Context) {
+		result, err := store.Take(keyFunc(c), rule)
+		if err != nil {
+			c.Error(err)
+			c.Next()
+			return
+		}
+		header := c.Res.Header()
+		header.Set(headerRateLimitLimit, strconv.Itoa(result.Limit))
+		header.Set(headerRateLimitRemaining, strconv.Itoa(result.Remaining))
+		header.Set(headerRateLimitReset, strconv.Itoa(ceilSeconds(result.Reset)))
+		if !result.Allowed {
+			header.Set(headerRetryAfter, strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			ErrorMessage(c, http.StatusTooManyRequests, "Too many requests")
+			return
+		}
+		c.Next()
+	}
+}
+
+func clientIPKey(c *Context) string {
+	return c.ClientIP()
+}
+
+func ceilSeconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int(math.Ceil(d.Seconds()))
+}
+
+type rateLimitEntry struct {
+	//tokens and updated are used by token bucket
+	tokens  float64
+	updated time.Time
+	//window, previous and current are used by sliding window
+	window   time.Time
+	previous int
+	current  int
+	expires  time.Time
+}
+
+//MemoryRateLimitStore is in memory RateLimitStore, expired keys are evicted periodically
+type MemoryRateLimitStore struct {
+	mutex     sync.Mutex
+	entries   map[string]*rateLimitEntry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+//NewMemoryRateLimitStore creates new in memory rate limit store
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		entries: make(map[string]*rateLimitEntry),
+		now:     time.Now,
+	}
+}
+
+//Take takes a request for the key
+func (ms *MemoryRateLimitStore) Take(key string, rule RateLimitRule) (RateLimitResult, error) {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+	now := ms.now()
+	if now.Sub(ms.lastSweep) >= rule.Window {
+		ms.sweep(now)
+	}
+	entry, ok := ms.entries[key]
+	if !ok {
+		entry = &rateLimitEntry{
+			tokens:  float64(rule.Burst),
+			updated: now,
+			window:  now.Truncate(rule.Window),
+		}
+		ms.entries[key] = entry
+	}
+	var result RateLimitResult
+	if rule.Algorithm == SlidingWindow {
+		result = entry.slidingWindow(now, rule)
+	} else {
+		result = entry.tokenBucket(now, rule)
+	}
+	entry.expires = now.Add(result.Reset)
+	return result, nil
+}
+
+//Len returns number of keys in the store
+func (ms *MemoryRateLimitStore) Len() int {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+	return len(ms.entries)
+}
+
+func (ms *MemoryRateLimitStore) sweep(now time.Time) {
+	for key, entry := range ms.entries {
+		if !now.Before(entry.expires) {
+			delete(ms.entries, key)
+		}
+	}
+	ms.lastSweep = now
+}
+
+func (entry *rateLimitEntry) tokenBucket(now time.Time, rule RateLimitRule) RateLimitResult {
+	rate := float64(rule.Limit) / float64(rule.Window)
+	burst := float64(rule.Burst)
+	entry.tokens = math.Min(burst, entry.tokens+float64(now.Sub(entry.updated))*rate)
+	entry.updated = now
+	result := RateLimitResult{Limit: rule.Burst}
+	if entry.tokens >= 1 {
+		entry.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - entry.tokens) / rate)
+	}
+	result.Remaining = int(entry.tokens)
+	result.Reset = time.Duration((burst - entry.tokens) / rate)
+	return result
+}
+
+func (entry *rateLimitEntry) slidingWindow(now time.Time, rule RateLimitRule) RateLimitResult {
+	window := now.Truncate(rule.Window)
+	if elapsed := window.Sub(entry.window); elapsed > 0 {
+		if elapsed == rule.Window {
+			entry.previous = entry.current
+		} else {
+			entry.previous = 0
+		}
+		entry.current = 0
+		entry.window = window
+	}
+	weight := 1 - float64(now.Sub(window))/float64(rule.Window)
+	count := int(math.Floor(float64(entry.previous)*weight)) + entry.current
+	result := RateLimitResult{Limit: rule.Limit}
+	if count < rule.Limit {
+		entry.current++
+		count++
+		result.Allowed = true
+	} else {
+		result.RetryAfter = window.Add(rule.Window).Sub(now)
+	}
+	result.Remaining = rule.Limit - count
+	if result.Remaining < 0 {
+		result.Remaining = 0
+	}
+	result.Reset = window.Add(2 * rule.Window).Sub(now)
+	if entry.current == 0 && entry.previous == 0 {
+		result.Reset = 0
+	}
+	return result
+}
@@ -0,0 +1,172 @@
+package mint
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/tabwriter"
+)
+
+//RouteInfo describes registered route
+type RouteInfo struct {
+	Methods []string `json:"methods,omitempty"`
+	//Path is full path template with group prefixes
+	Path string `json:"path"`
+	//Host is host template, empty if route matches any host
+	Host string `json:"host,omitempty"`
+	Name string `json:"name,omitempty"`
+	//Middleware is number of middleware run before handlers
+	Middleware int      `json:"middleware"`
+	Compressed bool     `json:"compressed"`
+	Schemes    []string `json:"schemes,omitempty"`
+	Headers    []string `json:"headers,omitempty"`
+	Queries    []string `json:"queries,omitempty"`
+}
+
+//Route templates of handlers which do not handle a route
+const (
+	NotFoundRoute         = "not_found"
+	MethodNotAllowedRoute = "method_not_allowed"
+)
+
+//Template returns route template of handler with patterns of variables
+//removed, so /users/{id:[0-9]+} is /users/{id}. It has low cardinality
+//and is used to name requests in metrics and traces
+func (hc *HandlerContext) Template() string {
+	if hc.template != emptyString {
+		return hc.template
+	}
+	return hc.routeTemplate()
+}
+
+func (hc *HandlerContext) routeTemplate() string {
+	if hc.Mint != nil {
+		switch hc {
+		case hc.Mint.notFoundHandler:
+			return NotFoundRoute
+		case hc.Mint.methodNotAllowed:
+			return MethodNotAllowedRoute
+		}
+	}
+	template := plainTemplate(hc.routeInfo().Path)
+	if hc.group != nil && hc.group.prefixHandler == hc {
+		template += "/*"
+	}
+	return template
+}
+
+//plainTemplate removes patterns of variables in route template
+func plainTemplate(tpl string) string {
+	vars := parseTemplate(tpl)
+	if len(vars) == 0 {
+		return tpl
+	}
+	var plain strings.Builder
+	last := 0
+	for _, variable := range vars {
+		plain.WriteString(tpl[last:variable.start])
+		plain.WriteString("{" + variable.name + "}")
+		last = variable.end
+	}
+	plain.WriteString(tpl[last:])
+	return plain.String()
+}
+
+//Routes returns information of routes registered in application
+func (mt *Mint) Routes() []RouteInfo {
+	app := mt.snapshot()
+	routes := make([]RouteInfo, 0, len(app.routes))
+	for _, hc := range app.routes {
+		routes = append(routes, hc.routeInfo())
+	}
+	return routes
+}
+
+func (hc *HandlerContext) routeInfo() RouteInfo {
+	info := RouteInfo{
+		Methods:    hc.methods,
+		Path:       hc.path,
+		Name:       hc.name,
+		Middleware: len(hc.middleware),
+		Compressed: hc.compressed,
+	}
+	//filters of groups are inherited, outer group filters come first
+	for group := hc.group; group != nil; group = group.parent {
+		if len(info.Methods) == 0 {
+			info.Methods = group.methods
+		}
+		info.Schemes = append(append([]string(nil), group.schemes...), info.Schemes...)
+		info.Headers = append(append([]string(nil), group.headers...), info.Headers...)
+		info.Queries = append(append([]string(nil), group.queries...), info.Queries...)
+	}
+	if hc.autoHead && !containsMethod(info.Methods, http.MethodHead) {
+		info.Methods = append(append([]string(nil), info.Methods...), http.MethodHead)
+	}
+	info.Schemes = append(info.Schemes, hc.schemes...)
+	info.Headers = append(info.Headers, hc.headers...)
+	info.Queries = append(info.Queries, hc.queries...)
+	if hc.route != nil {
+		if path, err := hc.route.GetPathTemplate(); err == nil {
+			info.Path = path
+		}
+		if host, err := hc.route.GetHostTemplate(); err == nil {
+			info.Host = host
+		}
+	}
+	return info
+}
+
+//PrintRoutes prints route table to w
+func (mt *Mint) PrintRoutes(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "METHODS\tPATH\tNAME\tMIDDLEWARE\tCOMPRESSED\tFILTERS")
+	for _, route := range mt.Routes() {
+		methods := "ANY"
+		if len(route.Methods) > 0 {
+			methods = strings.Join(route.Methods, ",")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%t\t%s\n",
+			methods,
+			route.Path,
+			orDash(route.Name),
+			route.Middleware,
+			route.Compressed,
+			orDash(route.filters()),
+		)
+	}
+	tw.Flush()
+}
+
+func (route *RouteInfo) filters() string {
+	var filters []string
+	if len(route.Host) > 0 {
+		filters = append(filters, "host="+route.Host)
+	}
+	if len(route.Schemes) > 0 {
+		filters = append(filters, "schemes="+strings.Join(route.Schemes, ","))
+	}
+	if len(route.Headers) > 0 {
+		filters = append(filters, "headers="+joinPairs(route.Headers))
+	}
+	if len(route.Queries) > 0 {
+		filters = append(filters, "queries="+joinPairs(route.Queries))
+	}
+	return strings.Join(filters, " ")
+}
+
+//joinPairs joins key value pairs as k1:v1,k2:v2
+func joinPairs(pairs []string) string {
+	joined := make([]string, 0, len(pairs)/2)
+	for iter := 0; iter+1 < len(pairs); iter += 2 {
+		joined = append(joined, pairs[iter]+":"+pairs[iter+1])
+	}
+	return strings.Join(joined, ",")
+}
+
+func orDash(value string) string {
+	if value == emptyString {
+		return "-"
+	}
+	return value
+}
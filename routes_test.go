@@ -0,0 +1,83 @@
+package mint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestRoutesDoesNotBuild(t *testing.T) {
+	mt := Simple()
+	mt.GET("/a", func(c *Context) { c.Status(http.StatusOK) })
+	if routes := mt.Routes(); len(routes) != 1 {
+		t.Fatalf("Routes() = %v, want 1 route", routes)
+	}
+	group := mt.Group("/g").Methods(http.MethodPost)
+	group.Handler(HandlerBuilder().Path("/b").Handle(func(c *Context) { c.Status(http.StatusCreated) }))
+	routes := mt.Routes()
+	if len(routes) != 2 {
+		t.Fatalf("Routes() = %v, want 2 routes", routes)
+	}
+	if want := []string{http.MethodGet, http.MethodHead}; !reflect.DeepEqual(routes[0].Methods, want) {
+		t.Errorf("Methods of GET route = %v, want %v", routes[0].Methods, want)
+	}
+	if want := "/g/b"; routes[1].Path != want {
+		t.Errorf("Path of group route = %q, want %q", routes[1].Path, want)
+	}
+	recorder := httptest.NewRecorder()
+	mt.Build().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/g/b", nil))
+	if recorder.Code != http.StatusCreated {
+		t.Errorf("status = %d, want %d", recorder.Code, http.StatusCreated)
+	}
+}
+
+func TestConfigureAfterBuildPanics(t *testing.T) {
+	mt := Simple()
+	group := mt.Group("/g")
+	hc := group.GET("/a", func(c *Context) {})
+	mt.Build()
+	for name, configure := range map[string]func(){
+		"GET":           func() { mt.GET("/b", func(c *Context) {}) },
+		"Use":           func() { mt.Use(func(c *Context) {}) },
+		"StrictSlash":   func() { mt.StrictSlash(true) },
+		"group GET":     func() { group.GET("/b", func(c *Context) {}) },
+		"group Methods": func() { group.Methods(http.MethodGet) },
+		"handler Path":  func() { hc.Path("/c") },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s after build did not panic", name)
+				}
+			}()
+			configure()
+		}()
+	}
+}
+
+func TestGroupMethodsAnswerHead(t *testing.T) {
+	mt := Simple()
+	group := mt.Group("/g").Methods(http.MethodGet)
+	group.Handler(HandlerBuilder().Path("/x").Handle(func(c *Context) { c.JSON(http.StatusOK, "body") }))
+	group.Handler(HandlerBuilder().Path("/y").Methods(http.MethodGet).Handle(func(c *Context) { c.JSON(http.StatusOK, "body") }))
+	if want := []string{http.MethodGet, http.MethodHead}; !reflect.DeepEqual(mt.Routes()[0].Methods, want) {
+		t.Errorf("Methods = %v, want %v", mt.Routes()[0].Methods, want)
+	}
+	app := mt.Build()
+	for _, path := range []string{"/g/x", "/g/y"} {
+		recorder := httptest.NewRecorder()
+		app.ServeHTTP(recorder, httptest.NewRequest(http.MethodHead, path, nil))
+		if recorder.Code != http.StatusOK {
+			t.Errorf("HEAD %s status = %d, want %d", path, recorder.Code, http.StatusOK)
+		}
+		if recorder.Body.Len() != 0 {
+			t.Errorf("HEAD %s body = %q, want empty", path, recorder.Body.String())
+		}
+	}
+	recorder := httptest.NewRecorder()
+	app.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/g/x", nil))
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d, want %d", recorder.Code, http.StatusMethodNotAllowed)
+	}
+}
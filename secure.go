@@ -0,0 +1,151 @@
+package mint
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+//Security headers
+const (
+	headerStrictTransportSecurity         = "Strict-Transport-Security"
+	headerContentTypeOptions              = "X-Content-Type-Options"
+	headerFrameOptions                    = "X-Frame-Options"
+	headerReferrerPolicy                  = "Referrer-Policy"
+	headerContentSecurityPolicy           = "Content-Security-Policy"
+	headerContentSecurityPolicyReportOnly = "Content-Security-Policy-Report-Only"
+	headerForwardedProto                  = "X-Forwarded-Proto"
+	//CSPNoncePlaceholder is placeholder in ContentSecurityPolicy replaced by nonce of the request
+	CSPNoncePlaceholder = "{nonce}"
+)
+
+//SecureConfig configures security headers middleware
+//Empty values disable the header
+type SecureConfig struct {
+	//HSTSMaxAge is max-age of Strict-Transport-Security in seconds
+	//it is sent only for HTTPS requests
+	HSTSMaxAge            int
+	HSTSIncludeSubdomains bool
+	HSTSPreload           bool
+	//ContentTypeOptions is value of X-Content-Type-Options such as "nosniff"
+	ContentTypeOptions string
+	//FrameOptions is value of X-Frame-Options such as "DENY" or "SAMEORIGIN"
+	FrameOptions string
+	//ReferrerPolicy is value of Referrer-Policy
+	ReferrerPolicy string
+	//ContentSecurityPolicy is value of Content-Security-Policy,
+	//CSPNoncePlaceholder is replaced by nonce generated for each request
+	//for example "script-src 'self' 'nonce-{nonce}'"
+	ContentSecurityPolicy string
+	//HTTPSRedirect redirects plain HTTP requests to HTTPS
+	HTTPSRedirect bool
+	//HTTPSHost is host used for redirection, default is request host
+	HTTPSHost string
+	//Development disables HSTS and HTTPS redirect and
+	//sends Content-Security-Policy as report only
+	Development bool
+}
+
+//DefaultSecureConfig is recommended security headers configuration
+var DefaultSecureConfig = SecureConfig{
+	HSTSMaxAge:            31536000,
+	HSTSIncludeSubdomains: true,
+	ContentTypeOptions:    "nosniff",
+	FrameOptions:          "DENY",
+	ReferrerPolicy:        "strict-origin-when-cross-origin",
+	ContentSecurityPolicy: "default-src 'self'",
+}
+
+//Secure creates security headers middleware
+func Secure(config SecureConfig) HandlerFunc {
+	var hsts string
+	if config.HSTSMaxAge > 0 && !config.Development {
+		hsts = "max-age=" + strconv.Itoa(config.HSTSMaxAge)
+		if config.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+		if config.HSTSPreload {
+			hsts += "; preload"
+		}
+	}
+	cspHeader := headerContentSecurityPolicy
+	if config.Development {
+		cspHeader = headerContentSecurityPolicyReportOnly
+	}
+	cspNonce := strings.Contains(config.ContentSecurityPolicy, CSPNoncePlaceholder)
+	redirect := config.HTTPSRedirect && !config.Development
+	return func(c *Context) {
+		https := isHTTPS(c.Req)
+		if redirect && !https {
+			host := config.HTTPSHost
+			if host == emptyString {
+				host = c.Req.Host
+			}
+			status := http.StatusMovedPermanently
+			if !isSafeMethod(c.Req.Method) {
+				status = http.StatusPermanentRedirect
+			}
+			http.Redirect(c.Res, c.Req, "https://"+host+c.Req.URL.RequestURI(), status)
+			c.status = status
+			return
+		}
+		header := c.Res.Header()
+		if hsts != emptyString && https {
+			header.Set(headerStrictTransportSecurity, hsts)
+		}
+		if config.ContentTypeOptions != emptyString {
+			header.Set(headerContentTypeOptions, config.ContentTypeOptions)
+		}
+		if config.FrameOptions != emptyString {
+			header.Set(headerFrameOptions, config.FrameOptions)
+		}
+		if config.ReferrerPolicy != emptyString {
+			header.Set(headerReferrerPolicy, config.ReferrerPolicy)
+		}
+		if config.ContentSecurityPolicy != emptyString {
+			csp := config.ContentSecurityPolicy
+			if cspNonce {
+				nonce, err := newCSPNonce()
+				if err != nil {
+					c.Error(err)
+					ErrorMessage(c, http.StatusInternalServerError, "Internal server error")
+					return
+				}
+				c.cspNonce = nonce
+				csp = strings.Replace(csp, CSPNoncePlaceholder, nonce, -1)
+			}
+			header.Set(cspHeader, csp)
+		}
+		c.Next()
+	}
+}
+
+func isHTTPS(req *http.Request) bool {
+	return req.TLS != nil || strings.EqualFold(req.Header.Get(headerForwardedProto), "https")
+}
+
+func newCSPNonce() (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return emptyString, err
+	}
+	return base64.StdEncoding.EncodeToString(nonce), nil
+}
+
+//CSPNonce returns Content-Security-Policy nonce of the request to be used in templates
+func (c *Context) CSPNonce() string {
+	return c.cspNonce
+}
+
+//Secure registers security headers middleware for whole application
+func (mt *Mint) Secure(config SecureConfig) *Mint {
+	mt.Use(Secure(config))
+	return mt
+}
+
+//Secure registers security headers middleware for the group
+func (hg *HandlersGroup) Secure(config SecureConfig) *HandlersGroup {
+	return hg.Use(Secure(config))
+}
@@ -0,0 +1,322 @@
+package mint
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+)
+
+//ErrScopeClosed is returned when scoped service is resolved after request finished
+var ErrScopeClosed = errors.New("mint: service scope is closed")
+
+//Lifetime decides how long instance of service lives
+type Lifetime int
+
+//Service lifetimes
+const (
+	//Singleton service is constructed once per application
+	Singleton Lifetime = iota
+	//Scoped service is constructed once per request and closed when request finishes
+	Scoped
+)
+
+var (
+	contextType = reflect.TypeOf((*Context)(nil))
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+//container is dependency injection container of application,
+//services are registered by type returned by their constructor
+type container struct {
+	mutex     sync.RWMutex
+	providers map[reflect.Type]*provider
+	values    map[string]interface{}
+	//closers are closed in reverse order of construction
+	closers   []io.Closer
+	closeOnce sync.Once
+}
+
+//provider constructs instances of service
+type provider struct {
+	lifetime    Lifetime
+	constructor reflect.Value
+	args        []reflect.Type
+	mutex       sync.Mutex
+	built       bool
+	instance    reflect.Value
+}
+
+//serviceScope holds scoped services of a request
+type serviceScope struct {
+	mutex     sync.Mutex
+	instances map[reflect.Type]reflect.Value
+	closers   []io.Closer
+	closed    bool
+}
+
+//closerFunc adapts Close() without error to io.Closer
+type closerFunc func()
+
+func (cf closerFunc) Close() error {
+	cf()
+	return nil
+}
+
+func newContainer() *container {
+	return &container{
+		providers: make(map[reflect.Type]*provider),
+		values:    make(map[string]interface{}),
+	}
+}
+
+//Provide registers service constructor with lifetime.
+//Constructor is func returning service or service and error, its arguments
+//are services resolved from container, scoped constructors can take *Context too.
+//Service implementing io.Closer or Close() is closed at shutdown for singleton
+//and at the end of request for scoped service.
+//It panics when constructor is not valid, as it is programming error found at startup
+func (mt *Mint) Provide(lifetime Lifetime, constructor interface{}) *Mint {
+	if err := mt.container.register(lifetime, constructor); err != nil {
+		panic(err)
+	}
+	return mt
+}
+
+//Singleton registers constructor of application wide service
+func (mt *Mint) Singleton(constructor interface{}) *Mint {
+	return mt.Provide(Singleton, constructor)
+}
+
+//Scoped registers constructor of per request service
+func (mt *Mint) Scoped(constructor interface{}) *Mint {
+	return mt.Provide(Scoped, constructor)
+}
+
+//Instance registers already constructed singleton service
+func (mt *Mint) Instance(service interface{}) *Mint {
+	value := reflect.ValueOf(service)
+	if !value.IsValid() {
+		panic("mint: service instance is nil")
+	}
+	mt.container.mutex.Lock()
+	mt.container.providers[value.Type()] = &provider{lifetime: Singleton, built: true, instance: value}
+	mt.container.mutex.Unlock()
+	return mt
+}
+
+//Service resolves singleton service into target, target is pointer to service type
+//
+//	var db *sql.DB
+//	err := mt.Service(&db)
+func (mt *Mint) Service(target interface{}) error {
+	return mt.container.resolveInto(target, nil)
+}
+
+//Service resolves singleton or scoped service into target, target is pointer to service type
+func (c *Context) Service(target interface{}) error {
+	return c.HandlerContext.Mint.container.resolveInto(target, c)
+}
+
+//MustService is like Service but panics if service cannot be resolved
+func (c *Context) MustService(target interface{}) {
+	if err := c.Service(target); err != nil {
+		panic(err)
+	}
+}
+
+func (ct *container) register(lifetime Lifetime, constructor interface{}) error {
+	value := reflect.ValueOf(constructor)
+	if value.Kind() != reflect.Func {
+		return fmt.Errorf("mint: service constructor must be func, got %T", constructor)
+	}
+	typ := value.Type()
+	if typ.NumOut() == 0 || typ.NumOut() > 2 || (typ.NumOut() == 2 && typ.Out(1) != errorType) {
+		return fmt.Errorf("mint: service constructor %s must return service or service and error", typ)
+	}
+	p := &provider{lifetime: lifetime, constructor: value}
+	for iter := 0; iter < typ.NumIn(); iter++ {
+		arg := typ.In(iter)
+		if arg == contextType && lifetime != Scoped {
+			return fmt.Errorf("mint: only scoped service constructor can take *Context, %s", typ)
+		}
+		p.args = append(p.args, arg)
+	}
+	ct.mutex.Lock()
+	ct.providers[typ.Out(0)] = p
+	ct.mutex.Unlock()
+	return nil
+}
+
+func (ct *container) resolveInto(target interface{}, c *Context) error {
+	pointer := reflect.ValueOf(target)
+	if pointer.Kind() != reflect.Ptr || pointer.IsNil() {
+		return fmt.Errorf("mint: service target must be non nil pointer, got %T", target)
+	}
+	value, err := ct.resolve(pointer.Elem().Type(), c, nil)
+	if err != nil {
+		return err
+	}
+	pointer.Elem().Set(value)
+	return nil
+}
+
+//resolve returns instance of service typ, resolving is list of
+//services being constructed and is used to detect dependency cycles
+func (ct *container) resolve(typ reflect.Type, c *Context, resolving []reflect.Type) (reflect.Value, error) {
+	if typ == contextType && c != nil {
+		return reflect.ValueOf(c), nil
+	}
+	for _, pending := range resolving {
+		if pending == typ {
+			return reflect.Value{}, fmt.Errorf("mint: dependency cycle resolving service %s", typ)
+		}
+	}
+	ct.mutex.RLock()
+	p, ok := ct.providers[typ]
+	ct.mutex.RUnlock()
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("mint: service %s is not registered", typ)
+	}
+	resolving = append(resolving, typ)
+	if p.lifetime == Scoped {
+		if c == nil {
+			return reflect.Value{}, fmt.Errorf("mint: scoped service %s can only be resolved in request", typ)
+		}
+		return ct.resolveScoped(typ, p, c, resolving)
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.built {
+		return p.instance, nil
+	}
+	//singletons never depend on request, so dependencies are resolved without it
+	instance, err := ct.construct(p, nil, resolving)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	p.instance, p.built = instance, true
+	if closer := asCloser(instance); closer != nil {
+		ct.mutex.Lock()
+		ct.closers = append(ct.closers, closer)
+		ct.mutex.Unlock()
+	}
+	return instance, nil
+}
+
+func (ct *container) resolveScoped(typ reflect.Type, p *provider, c *Context, resolving []reflect.Type) (reflect.Value, error) {
+	scope := c.scope
+	if scope == nil {
+		return reflect.Value{}, fmt.Errorf("mint: scoped service %s can only be resolved in request", typ)
+	}
+	scope.mutex.Lock()
+	instance, ok := scope.instances[typ]
+	closed := scope.closed
+	scope.mutex.Unlock()
+	if closed {
+		return reflect.Value{}, ErrScopeClosed
+	}
+	if ok {
+		return instance, nil
+	}
+	instance, err := ct.construct(p, c, resolving)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	scope.mutex.Lock()
+	defer scope.mutex.Unlock()
+	existing, ok := scope.instances[typ]
+	if ok || scope.closed {
+		//constructed concurrently by another goroutine of the request
+		//or request finished during construction
+		if closer := asCloser(instance); closer != nil {
+			closer.Close()
+		}
+		if !ok {
+			return reflect.Value{}, ErrScopeClosed
+		}
+		return existing, nil
+	}
+	if scope.instances == nil {
+		scope.instances = make(map[reflect.Type]reflect.Value)
+	}
+	scope.instances[typ] = instance
+	if closer := asCloser(instance); closer != nil {
+		scope.closers = append(scope.closers, closer)
+	}
+	return instance, nil
+}
+
+func (ct *container) construct(p *provider, c *Context, resolving []reflect.Type) (reflect.Value, error) {
+	args := make([]reflect.Value, len(p.args))
+	for index, arg := range p.args {
+		value, err := ct.resolve(arg, c, resolving)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		args[index] = value
+	}
+	out := p.constructor.Call(args)
+	if len(out) == 2 && !out[1].IsNil() {
+		return reflect.Value{}, out[1].Interface().(error)
+	}
+	return out[0], nil
+}
+
+//close closes constructed singletons in reverse order of construction
+func (ct *container) close() error {
+	var err error
+	ct.closeOnce.Do(func() {
+		ct.mutex.Lock()
+		closers := ct.closers
+		ct.closers = nil
+		ct.mutex.Unlock()
+		err = closeAll(closers)
+	})
+	return err
+}
+
+func asCloser(value reflect.Value) io.Closer {
+	if !value.IsValid() || !value.CanInterface() {
+		return nil
+	}
+	switch closer := value.Interface().(type) {
+	case io.Closer:
+		return closer
+	case interface{ Close() }:
+		return closerFunc(closer.Close)
+	}
+	return nil
+}
+
+//closeAll closes closers in reverse order and returns first error
+func closeAll(closers []io.Closer) error {
+	var err error
+	for iter := len(closers) - 1; iter >= 0; iter-- {
+		if closeErr := closers[iter].Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+//newServiceScope creates scope of a request, it is created before the chain runs
+//since goroutines of the request can resolve scoped services concurrently
+func newServiceScope() *serviceScope {
+	return new(serviceScope)
+}
+
+//closeScope closes scoped services of the request
+func (c *Context) closeScope() error {
+	scope := c.scope
+	if scope == nil {
+		return nil
+	}
+	scope.mutex.Lock()
+	closers := scope.closers
+	scope.closers = nil
+	scope.closed = true
+	scope.mutex.Unlock()
+	return closeAll(closers)
+}
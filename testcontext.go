@@ -0,0 +1,98 @@
+package mint
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+)
+
+//NewTestContext creates context for unit testing handlers and middleware without routing.
+//Context is bound to new application created by Simple, so services, store and
+//converters can be registered on c.HandlerContext.Mint
+//
+//	c := mint.NewTestContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/1", nil))
+//	c.SetParam("id", "1")
+//	c.SetHandlers(authMW, userHandler)
+//	c.Next()
+func NewTestContext(w http.ResponseWriter, req *http.Request) *Context {
+	mt := Simple()
+	hc := new(HandlerContext)
+	hc.Mint = mt
+	c := mt.newContext()
+	c.HandlerContext = hc
+	c.Req = req
+	c.Res = w
+	c.params = make(map[string]string)
+	c.scope = newServiceScope()
+	return c
+}
+
+//SetParam sets path parameter of context
+func (c *Context) SetParam(key, value string) {
+	if c.params == nil {
+		c.params = make(map[string]string)
+	}
+	c.params[key] = value
+	delete(c.paramValues, key)
+}
+
+//SetParams replaces path parameters of context
+func (c *Context) SetParams(params map[string]string) {
+	c.params = params
+	c.paramValues = nil
+}
+
+//SetHandlers sets chain run by Next and restarts it,
+//handler context is copied so route handling the request is not changed
+func (c *Context) SetHandlers(handlers ...HandlerFunc) {
+	hc := *c.HandlerContext
+	hc.handlers = chain(handlers)
+	hc.count = len(hc.handlers)
+	c.HandlerContext = &hc
+	c.index = 0
+}
+
+//RequestCapture records log output and context errors of a request sent with
+//context returned by WithRequestCapture, so test clients can inspect requests
+//without changing the application
+type RequestCapture struct {
+	//Log receives log output of the request instead of log output of application
+	Log    io.Writer
+	mutex  sync.Mutex
+	errors []error
+}
+
+//requestCaptureKey is key of request capture in request context
+type requestCaptureKey struct{}
+
+//WithRequestCapture returns copy of ctx carrying capture
+func WithRequestCapture(ctx context.Context, capture *RequestCapture) context.Context {
+	return context.WithValue(ctx, requestCaptureKey{}, capture)
+}
+
+//Errors returns errors recorded in context while serving the request
+func (rc *RequestCapture) Errors() []error {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+	return append([]error(nil), rc.errors...)
+}
+
+func (rc *RequestCapture) record(errors []error) {
+	rc.mutex.Lock()
+	rc.errors = append(rc.errors, errors...)
+	rc.mutex.Unlock()
+}
+
+func requestCapture(req *http.Request) *RequestCapture {
+	capture, _ := req.Context().Value(requestCaptureKey{}).(*RequestCapture)
+	return capture
+}
+
+//logWriter returns log output of the request
+func (c *Context) logWriter() io.Writer {
+	if capture := requestCapture(c.Req); capture != nil && capture.Log != nil {
+		return capture.Log
+	}
+	return c.HandlerContext.Mint.logWriter()
+}
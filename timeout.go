@@ -0,0 +1,135 @@
+package mint
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+//timeoutWriter buffers response of handler running with timeout,
+//writes after timeout are discarded
+type timeoutWriter struct {
+	mutex       sync.Mutex
+	header      http.Header
+	buffer      bytes.Buffer
+	status      int
+	wroteHeader bool
+	timedOut    bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) WriteHeader(status int) {
+	tw.mutex.Lock()
+	defer tw.mutex.Unlock()
+	if tw.timedOut || tw.wroteHeader {
+		return
+	}
+	tw.status = status
+	tw.wroteHeader = true
+}
+
+func (tw *timeoutWriter) Write(data []byte) (int, error) {
+	tw.mutex.Lock()
+	defer tw.mutex.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if !tw.wroteHeader {
+		tw.status = http.StatusOK
+		tw.wroteHeader = true
+	}
+	return tw.buffer.Write(data)
+}
+
+//flush writes buffered response to w
+func (tw *timeoutWriter) flush(w http.ResponseWriter) error {
+	tw.mutex.Lock()
+	defer tw.mutex.Unlock()
+	header := w.Header()
+	for key, values := range tw.header {
+		header[key] = values
+	}
+	if !tw.wroteHeader {
+		return nil
+	}
+	w.WriteHeader(tw.status)
+	_, err := w.Write(tw.buffer.Bytes())
+	return err
+}
+
+func (tw *timeoutWriter) timeout() {
+	tw.mutex.Lock()
+	tw.timedOut = true
+	tw.mutex.Unlock()
+}
+
+//Timeout creates middleware which runs rest of the chain with deadline,
+//c.Req carries context with the deadline so downstream calls can be cancelled.
+//If chain has not finished by the deadline, 503 response is sent and
+//later writes of the chain are discarded. Response is buffered until chain
+//finishes, so it does not implement http.Flusher and cannot be streamed.
+//Panic of the chain is logged with its stack and raised again if it happens before the deadline
+func Timeout(timeout time.Duration) HandlerFunc {
+	return func(c *Context) {
+		ctx, cancel := context.WithTimeout(c.Req.Context(), timeout)
+		defer cancel()
+		tw := &timeoutWriter{header: make(http.Header)}
+		for key, values := range c.Res.Header() {
+			tw.header[key] = append([]string(nil), values...)
+		}
+		//chain runs on copy of the context, so pooled context is not
+		//touched by the chain once it is abandoned after timeout
+		//scope is shared, so scoped services resolved by the chain are closed
+		inner := *c
+		inner.Req = c.Req.WithContext(ctx)
+		inner.Res = tw
+		inner.errors = append([]error(nil), c.errors...)
+		inner.keys = c.Keys()
+		done := make(chan struct{})
+		panicked := make(chan interface{}, 1)
+		//context is reset after timeout, so log writer is taken before chain runs
+		logOutput := c.logWriter()
+		go func() {
+			defer func() {
+				if p := recover(); p != nil {
+					//stack of the chain is lost when panic is raised again in request goroutine
+					fmt.Fprintf(logOutput, "[Mint] %v | panic in handler with timeout: %v\n%s", time.Now().Format("2006/01/02 - 15:04:05"), p, debug.Stack())
+					panicked <- p
+				}
+			}()
+			inner.Next()
+			close(done)
+		}()
+		select {
+		case p := <-panicked:
+			panic(p)
+		case <-done:
+			err := tw.flush(c.Res)
+			inner.Req = c.Req
+			inner.Res = c.Res
+			*c = inner
+			c.Error(err)
+		case <-ctx.Done():
+			tw.timeout()
+			c.Error(ctx.Err())
+			ErrorMessage(c, http.StatusServiceUnavailable, "Request timeout")
+		}
+	}
+}
+
+//Timeout sets timeout for the handler
+func (hc *HandlerContext) Timeout(timeout time.Duration) *HandlerContext {
+	return hc.Use(Timeout(timeout))
+}
+
+//Timeout sets timeout for handlers in the group
+func (hg *HandlersGroup) Timeout(timeout time.Duration) *HandlersGroup {
+	return hg.Use(Timeout(timeout))
+}
@@ -0,0 +1,465 @@
+package mint
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	mathrand "math/rand"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+//W3C Trace Context headers
+const (
+	HeaderTraceparent = "traceparent"
+	HeaderTracestate  = "tracestate"
+)
+
+const (
+	traceVersion = "00"
+	flagSampled  = 0x01
+	//maxTracestateMembers is maximum number of list members of tracestate
+	maxTracestateMembers = 32
+)
+
+//Span statuses
+const (
+	SpanStatusOK    = "ok"
+	SpanStatusError = "error"
+)
+
+//ErrInvalidTraceparent is returned when traceparent header cannot be parsed
+var ErrInvalidTraceparent = errors.New("mint: invalid traceparent")
+
+//TraceID is identifier of trace
+type TraceID [16]byte
+
+//SpanID is identifier of span
+type SpanID [8]byte
+
+//IsValid checks whether trace id is not all zeros
+func (id TraceID) IsValid() bool {
+	return id != TraceID{}
+}
+
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+//IsValid checks whether span id is not all zeros
+func (id SpanID) IsValid() bool {
+	return id != SpanID{}
+}
+
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+//SpanContext is propagated part of span
+type SpanContext struct {
+	TraceID    TraceID
+	SpanID     SpanID
+	Sampled    bool
+	TraceState string
+}
+
+//ParseTraceparent parses traceparent header value
+//version-traceid-spanid-flags such as 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+func ParseTraceparent(value string) (SpanContext, error) {
+	var sc SpanContext
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return sc, ErrInvalidTraceparent
+	}
+	//future versions can have more fields, version ff is invalid
+	if parts[0] == "ff" || (parts[0] == traceVersion && len(parts) != 4) {
+		return sc, ErrInvalidTraceparent
+	}
+	var version, flags [1]byte
+	if _, err := hex.Decode(version[:], []byte(parts[0])); err != nil {
+		return sc, ErrInvalidTraceparent
+	}
+	if _, err := hex.Decode(sc.TraceID[:], []byte(parts[1])); err != nil || strings.ToLower(parts[1]) != parts[1] {
+		return sc, ErrInvalidTraceparent
+	}
+	if _, err := hex.Decode(sc.SpanID[:], []byte(parts[2])); err != nil || strings.ToLower(parts[2]) != parts[2] {
+		return sc, ErrInvalidTraceparent
+	}
+	if _, err := hex.Decode(flags[:], []byte(parts[3])); err != nil {
+		return sc, ErrInvalidTraceparent
+	}
+	if !sc.TraceID.IsValid() || !sc.SpanID.IsValid() {
+		return sc, ErrInvalidTraceparent
+	}
+	sc.Sampled = flags[0]&flagSampled != 0
+	return sc, nil
+}
+
+//ValidTracestate checks tracestate header value, it is comma separated list of
+//at most 32 key=value members with unique keys such as rojo=00f067aa0ba902b7,congo=t61rcWkgMzE
+func ValidTracestate(value string) bool {
+	var keys []string
+	for _, member := range strings.Split(value, ",") {
+		member = strings.Trim(member, " \t")
+		if member == emptyString {
+			continue
+		}
+		index := strings.IndexByte(member, '=')
+		if index < 0 || len(keys) == maxTracestateMembers {
+			return false
+		}
+		key, val := member[:index], member[index+1:]
+		if !validTracestateKey(key) || !validTracestateValue(val) || containsString(keys, key) {
+			return false
+		}
+		keys = append(keys, key)
+	}
+	return true
+}
+
+//validTracestateKey checks key which is simple key or tenant@system multi tenant key
+func validTracestateKey(key string) bool {
+	if index := strings.IndexByte(key, '@'); index >= 0 {
+		tenant, system := key[:index], key[index+1:]
+		return len(tenant) > 0 && len(tenant) <= 241 && (isLowerAlpha(tenant[0]) || isDigit(tenant[0])) &&
+			len(system) > 0 && len(system) <= 14 && isLowerAlpha(system[0]) &&
+			validTracestateKeyChars(tenant) && validTracestateKeyChars(system)
+	}
+	return len(key) > 0 && len(key) <= 256 && isLowerAlpha(key[0]) && validTracestateKeyChars(key)
+}
+
+func validTracestateKeyChars(key string) bool {
+	for iter := 0; iter < len(key); iter++ {
+		char := key[iter]
+		if !isLowerAlpha(char) && !isDigit(char) && char != '_' && char != '-' && char != '*' && char != '/' {
+			return false
+		}
+	}
+	return true
+}
+
+//validTracestateValue checks value is printable ASCII without comma and equals sign
+func validTracestateValue(value string) bool {
+	if len(value) == 0 || len(value) > 256 || value[len(value)-1] == ' ' {
+		return false
+	}
+	for iter := 0; iter < len(value); iter++ {
+		char := value[iter]
+		if char < 0x20 || char > 0x7e || char == ',' || char == '=' {
+			return false
+		}
+	}
+	return true
+}
+
+func isLowerAlpha(char byte) bool {
+	return char >= 'a' && char <= 'z'
+}
+
+func isDigit(char byte) bool {
+	return char >= '0' && char <= '9'
+}
+
+//Traceparent formats span context as traceparent header value
+func (sc SpanContext) Traceparent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return traceVersion + "-" + sc.TraceID.String() + "-" + sc.SpanID.String() + "-" + flags
+}
+
+//Inject sets traceparent and tracestate headers of outgoing request
+func (sc SpanContext) Inject(header http.Header) {
+	header.Set(HeaderTraceparent, sc.Traceparent())
+	if sc.TraceState != emptyString {
+		header.Set(HeaderTracestate, sc.TraceState)
+	}
+}
+
+//Span is timed operation of a trace, span of request is created by Tracing
+//middleware and child spans are created using StartChild
+type Span struct {
+	Name         string
+	SpanContext  SpanContext
+	ParentSpanID SpanID
+	StartTime    time.Time
+	EndTime      time.Time
+	Status       string
+	Attributes   map[string]interface{}
+	Errors       []string
+	mutex        sync.Mutex
+	exporter     SpanExporter
+	ended        bool
+}
+
+//SpanExporter exports finished spans, only sampled spans are exported
+type SpanExporter interface {
+	ExportSpan(span *Span) error
+}
+
+func newSpan(name string, parent SpanContext, exporter SpanExporter) *Span {
+	span := &Span{
+		Name:       name,
+		StartTime:  time.Now(),
+		Status:     SpanStatusOK,
+		Attributes: make(map[string]interface{}),
+		exporter:   exporter,
+	}
+	span.SpanContext = parent
+	if parent.TraceID.IsValid() {
+		span.ParentSpanID = parent.SpanID
+	} else {
+		randomID(span.SpanContext.TraceID[:])
+	}
+	randomID(span.SpanContext.SpanID[:])
+	return span
+}
+
+//fallbackRand generates ids when crypto/rand fails, ids need to be unique, not secret
+var (
+	fallbackMutex sync.Mutex
+	fallbackRand  = mathrand.New(mathrand.NewSource(time.Now().UnixNano()))
+)
+
+//randomID fills id with random non zero bytes
+func randomID(id []byte) {
+	if _, err := rand.Read(id); err != nil {
+		fallbackMutex.Lock()
+		fallbackRand.Read(id)
+		fallbackMutex.Unlock()
+	}
+	for _, b := range id {
+		if b != 0 {
+			return
+		}
+	}
+	//all zero id is invalid
+	id[len(id)-1] = 1
+}
+
+//StartChild starts child span of span
+func (span *Span) StartChild(name string) *Span {
+	return newSpan(name, span.SpanContext, span.exporter)
+}
+
+//SetAttribute sets attribute of span
+func (span *Span) SetAttribute(key string, value interface{}) *Span {
+	span.mutex.Lock()
+	span.Attributes[key] = value
+	span.mutex.Unlock()
+	return span
+}
+
+//RecordError records error in span and sets its status to error
+func (span *Span) RecordError(err error) *Span {
+	if err == nil {
+		return span
+	}
+	span.mutex.Lock()
+	span.Errors = append(span.Errors, err.Error())
+	span.Status = SpanStatusError
+	span.mutex.Unlock()
+	return span
+}
+
+//SetStatus sets status of span
+func (span *Span) SetStatus(status string) *Span {
+	span.mutex.Lock()
+	span.Status = status
+	span.mutex.Unlock()
+	return span
+}
+
+//End ends span and exports it if sampled, span is ended only once
+func (span *Span) End() error {
+	span.mutex.Lock()
+	if span.ended {
+		span.mutex.Unlock()
+		return nil
+	}
+	span.ended = true
+	span.EndTime = time.Now()
+	span.mutex.Unlock()
+	if !span.SpanContext.Sampled || span.exporter == nil {
+		return nil
+	}
+	return span.exporter.ExportSpan(span)
+}
+
+//spanKey is key of span in request context
+type spanKey struct{}
+
+//ContextWithSpan returns copy of ctx carrying span
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+//SpanFromContext returns span carried by ctx, nil if there is none
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+//TracingConfig configures tracing
+type TracingConfig struct {
+	//Exporter exports finished spans, spans are not exported if it is nil,
+	//NewWriterExporter(os.Stdout) writes them as JSON lines
+	Exporter SpanExporter
+	//ServiceName is added to spans as service.name attribute
+	ServiceName string
+	//Sample decides whether trace started by the request is sampled,
+	//default samples all. Sampling decision of incoming traceparent is kept
+	Sample func(req *http.Request) bool
+	//ResponseTraceparent sets traceparent of the span on response, so clients
+	//can report trace of the request. W3C Trace Context defines it for requests only
+	ResponseTraceparent bool
+}
+
+//Tracing creates middleware which creates span for each request, named after
+//method and route template. Incoming traceparent and tracestate headers are continued.
+//Span is available using c.Span() and SpanFromContext(c.Req.Context())
+func Tracing(config TracingConfig) HandlerFunc {
+	return func(c *Context) {
+		parent, err := ParseTraceparent(c.Req.Header.Get(HeaderTraceparent))
+		if err == nil {
+			//multiple tracestate headers form single list, invalid tracestate is discarded
+			traceState := strings.Join(c.Req.Header[http.CanonicalHeaderKey(HeaderTracestate)], ",")
+			if ValidTracestate(traceState) {
+				parent.TraceState = traceState
+			}
+		} else {
+			parent = SpanContext{Sampled: config.Sample == nil || config.Sample(c.Req)}
+		}
+		template := c.HandlerContext.Template()
+		span := newSpan(c.Req.Method+" "+template, parent, config.Exporter)
+		span.Attributes["http.method"] = c.Req.Method
+		span.Attributes["http.route"] = template
+		span.Attributes["http.target"] = c.Req.URL.RequestURI()
+		span.Attributes["http.client_ip"] = c.ClientIP()
+		if config.ServiceName != emptyString {
+			span.Attributes["service.name"] = config.ServiceName
+		}
+		c.span = span
+		c.Req = c.Req.WithContext(ContextWithSpan(c.Req.Context(), span))
+		if config.ResponseTraceparent {
+			span.SpanContext.Inject(c.Res.Header())
+		}
+		//span is ended even if handler panics, so it is exported
+		finished := false
+		defer func() {
+			status := c.responseStatus(finished)
+			span.SetAttribute("http.status_code", status)
+			for _, err := range c.errors {
+				span.RecordError(err)
+			}
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(SpanStatusError)
+			}
+			if err := span.End(); err != nil {
+				c.Error(err)
+			}
+		}()
+		c.Next()
+		finished = true
+	}
+}
+
+//Tracing enables tracing of all requests
+func (mt *Mint) Tracing(config TracingConfig) *Mint {
+	mt.Use(Tracing(config))
+	return mt
+}
+
+//Span returns span of the request, nil if tracing is not enabled
+func (c *Context) Span() *Span {
+	return c.span
+}
+
+//StartSpan starts child span of request span, new trace is started
+//if tracing is not enabled, such span is not exported
+func (c *Context) StartSpan(name string) *Span {
+	if c.span == nil {
+		return newSpan(name, SpanContext{}, nil)
+	}
+	return c.span.StartChild(name)
+}
+
+//WriterExporter writes spans to writer as JSON lines
+type WriterExporter struct {
+	mutex  sync.Mutex
+	writer io.Writer
+	closer io.Closer
+}
+
+//spanRecord is JSON form of span
+type spanRecord struct {
+	Name         string                 `json:"name"`
+	TraceID      string                 `json:"trace_id"`
+	SpanID       string                 `json:"span_id"`
+	ParentSpanID string                 `json:"parent_span_id,omitempty"`
+	TraceState   string                 `json:"trace_state,omitempty"`
+	Start        time.Time              `json:"start"`
+	End          time.Time              `json:"end"`
+	DurationMS   float64                `json:"duration_ms"`
+	Status       string                 `json:"status"`
+	Attributes   map[string]interface{} `json:"attributes,omitempty"`
+	Errors       []string               `json:"errors,omitempty"`
+}
+
+//NewWriterExporter creates exporter writing spans to w
+func NewWriterExporter(w io.Writer) *WriterExporter {
+	return &WriterExporter{writer: w}
+}
+
+//NewFileExporter creates exporter appending spans to file at path
+func NewFileExporter(path string) (*WriterExporter, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &WriterExporter{writer: file, closer: file}, nil
+}
+
+//ExportSpan writes span as JSON line
+func (we *WriterExporter) ExportSpan(span *Span) error {
+	span.mutex.Lock()
+	record := spanRecord{
+		Name:       span.Name,
+		TraceID:    span.SpanContext.TraceID.String(),
+		SpanID:     span.SpanContext.SpanID.String(),
+		TraceState: span.SpanContext.TraceState,
+		Start:      span.StartTime,
+		End:        span.EndTime,
+		DurationMS: float64(span.EndTime.Sub(span.StartTime)) / float64(time.Millisecond),
+		Status:     span.Status,
+		Attributes: span.Attributes,
+		Errors:     span.Errors,
+	}
+	if span.ParentSpanID.IsValid() {
+		record.ParentSpanID = span.ParentSpanID.String()
+	}
+	data, err := json.Marshal(record)
+	span.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+	we.mutex.Lock()
+	defer we.mutex.Unlock()
+	_, err = we.writer.Write(append(data, '\n'))
+	return err
+}
+
+//Close closes file of exporter created by NewFileExporter
+func (we *WriterExporter) Close() error {
+	if we.closer == nil {
+		return nil
+	}
+	return we.closer.Close()
+}
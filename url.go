@@ -0,0 +1,118 @@
+package mint
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+//URL builds URL of the named route, params are key value pairs
+//Params which are not variables of the route are added to query string
+func (mt *Mint) URL(name string, params ...string) (string, error) {
+	route := mt.snapshot().router.Get(name)
+	if route == nil {
+		return emptyString, fmt.Errorf("mint: route %s not found", name)
+	}
+	if len(params)%2 != 0 {
+		return emptyString, fmt.Errorf("mint: params of route %s must be key value pairs, got %v", name, params)
+	}
+	vars := routeVars(route)
+	pairs := make([]string, 0, len(params))
+	provided := make(map[string]bool, len(vars))
+	query := make(url.Values)
+	for iter := 0; iter < len(params); iter += 2 {
+		key, value := params[iter], params[iter+1]
+		if containsString(vars, key) {
+			pairs = append(pairs, key, value)
+			provided[key] = true
+		} else {
+			query.Add(key, value)
+		}
+	}
+	for _, key := range vars {
+		if !provided[key] {
+			return emptyString, fmt.Errorf("mint: missing parameter %s for route %s", key, name)
+		}
+	}
+	u, err := route.URL(pairs...)
+	if err != nil {
+		return emptyString, err
+	}
+	if len(query) > 0 {
+		if u.RawQuery != emptyString {
+			u.RawQuery += "&"
+		}
+		u.RawQuery += query.Encode()
+	}
+	return u.String(), nil
+}
+
+//URLFor builds URL of the named route
+func (c *Context) URLFor(name string, params ...string) (string, error) {
+	return c.HandlerContext.Mint.URL(name, params...)
+}
+
+//routeVars returns variables in host, path and queries templates of route
+func routeVars(route *mux.Route) []string {
+	var vars []string
+	if host, err := route.GetHostTemplate(); err == nil {
+		vars = append(vars, templateVars(host)...)
+	}
+	if path, err := route.GetPathTemplate(); err == nil {
+		vars = append(vars, templateVars(path)...)
+	}
+	if queries, err := route.GetQueriesTemplates(); err == nil {
+		for _, query := range queries {
+			vars = append(vars, templateVars(query)...)
+		}
+	}
+	return vars
+}
+
+//templateVar is variable in route template such as {id:[0-9]+}
+type templateVar struct {
+	name    string
+	pattern string
+	//start and end are indexes of braces in the template
+	start int
+	end   int
+}
+
+//parseTemplate returns variables in route template
+func parseTemplate(tpl string) []templateVar {
+	var vars []templateVar
+	level, start := 0, 0
+	for iter := 0; iter < len(tpl); iter++ {
+		switch tpl[iter] {
+		case '{':
+			if level == 0 {
+				start = iter
+			}
+			level++
+		case '}':
+			level--
+			if level == 0 {
+				variable := templateVar{start: start, end: iter + 1}
+				parts := strings.SplitN(tpl[start+1:iter], ":", 2)
+				variable.name = strings.TrimSpace(parts[0])
+				if len(parts) == 2 {
+					variable.pattern = parts[1]
+				}
+				vars = append(vars, variable)
+			}
+		}
+	}
+	return vars
+}
+
+//templateVars returns names of variables in route template
+func templateVars(tpl string) []string {
+	vars := parseTemplate(tpl)
+	names := make([]string, len(vars))
+	for index, variable := range vars {
+		names[index] = variable.name
+	}
+	return names
+}
@@ -0,0 +1,247 @@
+package mint
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+//Versioning headers
+const (
+	DefaultVersionHeader = "X-API-Version"
+	headerAccept         = "Accept"
+	headerDeprecation    = "Deprecation"
+	headerSunset         = "Sunset"
+	headerLink           = "Link"
+)
+
+//VersionStrategy decides how version of the request is selected
+type VersionStrategy int
+
+//Version strategies
+const (
+	//VersionByPath selects version by path prefix such as /api/v1
+	VersionByPath VersionStrategy = iota
+	//VersionByAccept selects version by version parameter of Accept media type
+	//such as application/json; version=2
+	VersionByAccept
+	//VersionByHeader selects version by custom request header
+	VersionByHeader
+)
+
+//VersionConfig configures versioned groups
+type VersionConfig struct {
+	Strategy VersionStrategy
+	//Prefix is path prefix of all versions
+	Prefix string
+	//Header is request header containing version, default is X-API-Version
+	Header string
+	//Default is version used when request does not select one,
+	//default is latest version. It is not used by VersionByPath
+	Default string
+}
+
+//Versions is set of versioned groups, versions are registered from oldest to newest.
+//Handler or group missing in a version falls back to the latest older version having it
+type Versions struct {
+	mint     *Mint
+	config   VersionConfig
+	versions []*apiVersion
+}
+
+type apiVersion struct {
+	name  string
+	group *HandlersGroup
+}
+
+//Versions creates set of versioned groups
+func (mt *Mint) Versions(config VersionConfig) *Versions {
+	mt.checkNotBuilt()
+	if config.Header == emptyString {
+		config.Header = DefaultVersionHeader
+	}
+	vs := &Versions{mint: mt, config: config}
+	mt.versions = append(mt.versions, vs)
+	return vs
+}
+
+//Version creates group for the version
+func (vs *Versions) Version(name string) *HandlersGroup {
+	group := new(HandlersGroup)
+	version := &apiVersion{name: name, group: group}
+	if vs.config.Strategy == VersionByPath {
+		group.basePath = strings.TrimSuffix(vs.config.Prefix, "/") + "/" + name
+	} else {
+		group.basePath = vs.config.Prefix
+		group.matcher = vs.matcher(version)
+	}
+	//responses of header and media type versions depend on request header,
+	//so caches must not share them between versions
+	vary := emptyString
+	switch vs.config.Strategy {
+	case VersionByHeader:
+		vary = vs.config.Header
+	case VersionByAccept:
+		vary = headerAccept
+	}
+	group.Use(func(c *Context) {
+		c.apiVersion = name
+		if vary != emptyString {
+			c.Res.Header().Add(headerVary, vary)
+		}
+		c.Next()
+	})
+	vs.versions = append(vs.versions, version)
+	vs.mint.AddGroup(group)
+	return group
+}
+
+//Deprecate marks version deprecated, responses of the version have
+//Deprecation header, Sunset header when sunset is not zero and
+//Link header when link is not empty
+func (vs *Versions) Deprecate(name string, sunset time.Time, link string) *Versions {
+	for _, version := range vs.versions {
+		if version.name != name {
+			continue
+		}
+		version.group.Use(func(c *Context) {
+			header := c.Res.Header()
+			header.Set(headerDeprecation, "true")
+			if !sunset.IsZero() {
+				header.Set(headerSunset, sunset.UTC().Format(http.TimeFormat))
+			}
+			if link != emptyString {
+				header.Add(headerLink, "<"+link+`>; rel="deprecation"`)
+			}
+			c.Next()
+		})
+	}
+	return vs
+}
+
+func (vs *Versions) matcher(version *apiVersion) mux.MatcherFunc {
+	return func(req *http.Request, match *mux.RouteMatch) bool {
+		requested := vs.requestedVersion(req)
+		if requested == emptyString {
+			requested = vs.config.Default
+			if requested == emptyString {
+				requested = vs.versions[len(vs.versions)-1].name
+			}
+		}
+		return normalizeVersion(requested) == normalizeVersion(version.name)
+	}
+}
+
+func (vs *Versions) requestedVersion(req *http.Request) string {
+	if vs.config.Strategy == VersionByHeader {
+		return strings.TrimSpace(req.Header.Get(vs.config.Header))
+	}
+	for _, accept := range strings.Split(req.Header.Get(headerAccept), ",") {
+		_, params, err := mime.ParseMediaType(accept)
+		if err != nil {
+			continue
+		}
+		if version, ok := params["version"]; ok {
+			return version
+		}
+	}
+	return emptyString
+}
+
+//normalizeVersion makes v2 and 2 same
+func normalizeVersion(version string) string {
+	return strings.TrimPrefix(strings.ToLower(strings.TrimSpace(version)), "v")
+}
+
+//addFallbacks adds copies of handlers and groups from older versions
+//to newer versions which do not have them
+func (vs *Versions) addFallbacks() {
+	for newer := len(vs.versions) - 1; newer > 0; newer-- {
+		group := vs.versions[newer].group
+		for older := newer - 1; older >= 0; older-- {
+			addFallbacks(group, vs.versions[older].group)
+		}
+	}
+}
+
+//addFallbacks adds copies of handlers and subgroups of older group missing in group,
+//subgroups having same path and host are merged
+func addFallbacks(group *HandlersGroup, older *HandlersGroup) {
+	for _, hc := range older.handlers {
+		if !hasRoute(group.handlers, hc) {
+			group.handlers = append(group.handlers, fallbackHandler(hc))
+		}
+	}
+	for _, subgroup := range older.handlersGroup {
+		same := findGroup(group.handlersGroup, subgroup)
+		if same == nil {
+			group.handlersGroup = append(group.handlersGroup, fallbackGroup(subgroup))
+			continue
+		}
+		//prefix handler handles whole subtree of its group
+		if same.prefixHandler == nil && subgroup.prefixHandler == nil {
+			addFallbacks(same, subgroup)
+		}
+	}
+}
+
+func findGroup(groups []*HandlersGroup, hg *HandlersGroup) *HandlersGroup {
+	for _, group := range groups {
+		if group.basePath == hg.basePath && group.host == hg.host {
+			return group
+		}
+	}
+	return nil
+}
+
+//fallbackHandler copies handler without its name, names must be unique
+func fallbackHandler(hc *HandlerContext) *HandlerContext {
+	fallback := *hc
+	fallback.name = emptyString
+	fallback.middleware = chain(hc.middleware)
+	fallback.handlers = chain(hc.handlers)
+	return &fallback
+}
+
+func fallbackGroup(hg *HandlersGroup) *HandlersGroup {
+	fallback := *hg
+	fallback.middleware = chain(hg.middleware)
+	if hg.prefixHandler != nil {
+		fallback.prefixHandler = fallbackHandler(hg.prefixHandler)
+	}
+	fallback.handlers = make([]*HandlerContext, len(hg.handlers))
+	for index, hc := range hg.handlers {
+		fallback.handlers[index] = fallbackHandler(hc)
+	}
+	fallback.handlersGroup = make([]*HandlersGroup, len(hg.handlersGroup))
+	for index, group := range hg.handlersGroup {
+		fallback.handlersGroup[index] = fallbackGroup(group)
+	}
+	return &fallback
+}
+
+//hasRoute checks whether handlers contain handler for same path and method as hc
+func hasRoute(handlers []*HandlerContext, hc *HandlerContext) bool {
+	for _, handler := range handlers {
+		if handler.path != hc.path || handler.host != hc.host {
+			continue
+		}
+		if len(handler.methods) == 0 || len(hc.methods) == 0 {
+			return true
+		}
+		for _, method := range hc.methods {
+			if containsMethod(handler.methods, method) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+//APIVersion returns version of versioned group handling the request
+func (c *Context) APIVersion() string {
+	return c.apiVersion
+}
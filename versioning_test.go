@@ -0,0 +1,37 @@
+package mint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVersionFallbackToNestedGroups(t *testing.T) {
+	mt := Simple()
+	versions := mt.Versions(VersionConfig{Strategy: VersionByPath, Prefix: "/api"})
+	v1 := versions.Version("v1")
+	v1.Group("/users").GET("/list", func(c *Context) { c.JSON(http.StatusOK, c.APIVersion()) })
+	v1.Group("/orders").GET("/list", func(c *Context) { c.JSON(http.StatusOK, c.APIVersion()) })
+	files := v1.Group("/files")
+	files.PrefixHandler(HandlerBuilder().Handle(func(c *Context) { c.Status(http.StatusAccepted) }))
+	v2 := versions.Version("v2")
+	v2.Group("/users").GET("/new", func(c *Context) { c.JSON(http.StatusOK, c.APIVersion()) })
+	app := mt.Build()
+	tests := []struct {
+		path string
+		code int
+	}{
+		{"/api/v2/users/new", http.StatusOK},
+		{"/api/v2/users/list", http.StatusOK},
+		{"/api/v2/orders/list", http.StatusOK},
+		{"/api/v2/files/a/b", http.StatusAccepted},
+		{"/api/v1/users/new", http.StatusNotFound},
+	}
+	for _, test := range tests {
+		recorder := httptest.NewRecorder()
+		app.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, test.path, nil))
+		if recorder.Code != test.code {
+			t.Errorf("GET %s status = %d, want %d", test.path, recorder.Code, test.code)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package mint
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+)
+
+//sizeWriter records status and size of response written by net/http handlers
+//into context, mint writes are not recorded as context records them itself
+type sizeWriter struct {
+	http.ResponseWriter
+	c        *Context
+	tracking bool
+}
+
+func (sw *sizeWriter) WriteHeader(status int) {
+	if sw.tracking {
+		sw.c.status = status
+	}
+	sw.ResponseWriter.WriteHeader(status)
+}
+
+func (sw *sizeWriter) Write(data []byte) (int, error) {
+	size, err := sw.ResponseWriter.Write(data)
+	if sw.tracking {
+		if sw.c.status == 0 {
+			sw.c.status = http.StatusOK
+		}
+		sw.c.setSize(size)
+	}
+	return size, err
+}
+
+//Flush implements http.Flusher
+func (sw *sizeWriter) Flush() {
+	if flusher, ok := sw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+//Hijack implements http.Hijacker, so wrapped handlers can upgrade connection
+func (sw *sizeWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := sw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("mint: response writer does not support hijacking")
+	}
+	return hijacker.Hijack()
+}
+
+//Push implements http.Pusher
+func (sw *sizeWriter) Push(target string, opts *http.PushOptions) error {
+	pusher, ok := sw.ResponseWriter.(http.Pusher)
+	if !ok {
+		return http.ErrNotSupported
+	}
+	return pusher.Push(target, opts)
+}
+
+//WrapH converts http.Handler to HandlerFunc
+func WrapH(handler http.Handler) HandlerFunc {
+	return func(c *Context) {
+		handler.ServeHTTP(&sizeWriter{ResponseWriter: c.Res, c: c, tracking: true}, c.Req)
+	}
+}
+
+//WrapF converts http.HandlerFunc to HandlerFunc
+func WrapF(handler http.HandlerFunc) HandlerFunc {
+	return WrapH(handler)
+}
+
+//WrapMiddleware converts net/http middleware to HandlerFunc
+//rest of the chain runs inside the middleware with request and
+//response writer passed by it, so its wrappers are preserved
+func WrapMiddleware(middleware func(http.Handler) http.Handler) HandlerFunc {
+	return func(c *Context) {
+		req, res := c.Req, c.Res
+		writer := &sizeWriter{ResponseWriter: res, c: c, tracking: true}
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			writer.tracking = false
+			c.Req = r
+			if w == http.ResponseWriter(writer) {
+				c.Res = res
+			} else {
+				c.Res = w
+			}
+			c.Next()
+			writer.tracking = true
+		})
+		middleware(next).ServeHTTP(writer, req)
+		c.Req, c.Res = req, res
+	}
+}
+
+//Mount mounts http.Handler at path prefix, prefix is stripped from request path
+//Application middleware runs before the handler
+func (mt *Mint) Mount(prefix string, handler http.Handler) *HandlerContext {
+	hc := HandlerBuilder().Handle(WrapH(http.StripPrefix(strings.TrimSuffix(prefix, "/"), handler)))
+	group := NewGroup(prefix)
+	group.PrefixHandler(hc)
+	mt.AddGroup(group)
+	return hc
+}